@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/smartcat999/helm-ui/internal/api"
 	"github.com/smartcat999/helm-ui/internal/service"
 )
@@ -13,17 +16,48 @@ func main() {
 	// 创建 Helm 服务
 	helmService := service.NewHelmService()
 
+	// 注册 values 转换器
+	if aliases := os.Getenv("HELM_UI_REGISTRY_ALIASES"); aliases != "" {
+		helmService.RegisterTransformer(service.NewRegistryAliasTransformer(aliases))
+	}
+	// values 中的 ${env:NAME} 引用只能使用 HELM_UI_ALLOWED_ENV_VARS 中列出的变量
+	if os.Getenv("HELM_UI_ENABLE_ENV_SUBST") == "true" {
+		helmService.RegisterTransformer(service.NewEnvSubstTransformer(os.Getenv("HELM_UI_ALLOWED_ENV_VARS")))
+	}
+
+	// 创建仓库服务并在后台定期刷新仓库索引
+	repoService := service.NewRepoService(helmService)
+	repoService.StartRefresher(context.Background())
+
+	// 定期清理临时目录中残留的文件
+	helmService.StartTempJanitor(context.Background())
+
+	// 创建审计日志，HELM_UI_AUDIT_LOG 为 stdout 或文件路径
+	auditLog, err := service.NewAuditLogger(os.Getenv("HELM_UI_AUDIT_LOG"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// 创建 API 处理器
-	handler := api.NewHandler(helmService)
+	handler := api.NewHandler(helmService, repoService, auditLog)
+
+	// 注册监控指标
+	api.RegisterMetrics(helmService)
 
 	// 设置路由
 	r := gin.Default()
+	// 管理接口的认证中间件，未配置 HELM_UI_ADMIN_TOKEN 时默认拒绝
+	adminAuth := api.AdminAuth()
+	// 只信任 HELM_UI_TRUSTED_PROXIES 中的代理转发的客户端地址
+	if err := r.SetTrustedProxies(api.TrustedProxies()); err != nil {
+		log.Fatal(err)
+	}
 
 	// 允许跨域
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, PATCH, DELETE")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Content-Range, Accept-Encoding, Authorization, X-Cluster-Session, X-Render-Session, If-Modified-Since")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -31,14 +65,89 @@ func main() {
 		c.Next()
 	})
 
+	// 携带 X-Cluster-Session 头的请求使用会话上传的 kubeconfig 访问集群
+	r.Use(handler.ClusterSession())
+
 	// API 路由
-	r.POST("/api/charts", handler.UploadChart)
-	r.POST("/api/charts/dir", handler.UploadChartDir)
+	r.POST("/api/charts", handler.Audit("chart.upload"), handler.UploadChart)
+	r.POST("/api/charts/dir", handler.Audit("chart.upload"), handler.UploadChartDir)
+	r.POST("/api/charts/base64", handler.Audit("chart.upload"), handler.UploadChartBase64)
+	r.POST("/api/charts/oci", handler.Audit("chart.upload"), handler.PullChartFromOCI)
+	r.POST("/api/charts/dir/register", adminAuth, handler.Audit("chart.register"), handler.RegisterChartDir)
+	r.POST("/api/charts/upload/init", handler.InitChartUpload)
+	r.GET("/api/charts/upload/:id", handler.GetChartUpload)
+	r.PATCH("/api/charts/upload/:id", handler.PatchChartUpload)
+	r.POST("/api/charts/upload/:id/complete", handler.Audit("chart.upload"), handler.CompleteChartUpload)
 	r.GET("/api/charts", handler.ListCharts)
+	r.GET("/api/charts/export", handler.ExportCharts)
+	r.POST("/api/charts/import", adminAuth, handler.Audit("chart.import"), handler.ImportCharts)
 	r.GET("/api/charts/:name/versions", handler.ListChartVersions)
+	r.POST("/api/charts/:name/prune", handler.Audit("chart.delete"), handler.PruneChartVersions)
+	r.POST("/api/charts/:name/delete", handler.Audit("chart.delete"), handler.DeleteChartVersions)
+	r.GET("/api/charts/:name/updates", handler.CheckForUpdates)
 	r.GET("/api/charts/:name/:version/files", handler.ListChartFiles)
 	r.POST("/api/charts/:name/:version/render", handler.RenderChart)
+	r.POST("/api/charts/:name/:version/render/stream", handler.RenderChartStream)
+	r.POST("/api/charts/:name/:version/render/summary", handler.RenderSummary)
+	r.POST("/api/charts/:name/:version/render/documents", handler.RenderDocuments)
+	r.POST("/api/charts/:name/:version/render/zip", handler.RenderChartZip)
+	r.POST("/api/charts/:name/:version/render/diff-values", handler.DiffValuesRender)
+	r.POST("/api/charts/:name/:version/render/cluster-diff", handler.ClusterDiff)
+	r.POST("/api/charts/:name/:version/preflight", handler.Preflight)
+	r.POST("/api/charts/:name/:version/render/file/*path", handler.RenderChartFile)
+	r.POST("/api/charts/:name/:version/notes", handler.RenderNotes)
 	r.GET("/api/charts/:name/:version/values", handler.GetChartValues)
+	r.GET("/api/charts/:name/:version/values/docs", handler.GetValuesDocs)
+	r.GET("/api/charts/:name/:version/values/annotated", handler.GetAnnotatedValues)
+	r.GET("/api/charts/:name/:version/values/types", handler.GetValuesTypes)
+	r.GET("/api/charts/:name/:version/values/full", handler.GetFullValues)
+	r.POST("/api/charts/:name/:version/values/coalesced", handler.GetCoalescedValues)
+	r.POST("/api/charts/:name/:version/values/flatten", handler.FlattenValues)
+	r.POST("/api/charts/:name/:version/values/check-required", handler.CheckRequiredValues)
+	r.POST("/api/charts/:name/:version/values/merge-provenance", handler.MergeProvenance)
+	r.POST("/api/charts/:name/:version/install/upload", handler.Audit("release.install"), handler.InstallChartUpload)
+	r.GET("/api/charts/:name/:version/metadata", handler.GetChartMetadata)
+	r.GET("/api/charts/:name/:version/download", handler.DownloadChart)
+	r.GET("/api/charts/:name/:version/digest", handler.GetChartDigest)
+	r.POST("/api/charts/:name/render/all", handler.RenderAllVersions)
+	r.POST("/api/charts/:name/diff/summary", handler.UpgradeImpact)
+	r.POST("/api/charts/:name/profiles/:profile", handler.Audit("profile.save"), handler.SaveProfile)
+	r.GET("/api/charts/:name/profiles/:profile", handler.GetProfile)
+	r.DELETE("/api/charts/:name/profiles/:profile", handler.Audit("profile.delete"), handler.DeleteProfile)
+	r.GET("/api/charts/:name/:version/icon", handler.GetChartIcon)
+	r.GET("/api/charts/:name/:version/tests", handler.ListChartTests)
+	r.GET("/api/charts/:name/:version/tree", handler.GetChartTree)
+	r.GET("/api/charts/:name/:version/graph", handler.GetDependencyGraph)
+	r.GET("/api/charts/:name/:version/template-refs", handler.GetTemplateReferences)
+	r.GET("/api/charts/:name/:version/dependencies/status", handler.GetDependenciesStatus)
+	r.GET("/api/render/history", handler.ListRenderHistory)
+	r.POST("/api/render/replay/:id", handler.ReplayRender)
+	r.GET("/api/releases", handler.ListReleases)
+	r.GET("/api/releases/:name/manifest", handler.GetReleaseManifest)
+	r.GET("/api/releases/:name/resources", handler.GetReleaseResources)
+	r.POST("/api/releases/:name/diff", handler.UpgradeDiff)
+	r.POST("/api/releases/:name/test", handler.Audit("release.test"), handler.RunReleaseTests)
+	r.GET("/api/namespaces", handler.ListNamespaces)
+	r.GET("/api/namespaces/:ns/defaults", handler.GetNamespaceDefaults)
+	r.POST("/api/policy/evaluate", handler.EvaluatePolicy)
+	r.POST("/api/analyze", handler.AnalyzeManifests)
+	r.GET("/api/repos", handler.ListRepos)
+	r.POST("/api/repos", handler.Audit("repo.add"), handler.AddRepo)
+	r.DELETE("/api/repos/:name", handler.Audit("repo.remove"), handler.RemoveRepo)
+	r.POST("/api/repos/:name/refresh", handler.RefreshRepo)
+	r.POST("/api/cluster/config", handler.Audit("cluster.config"), handler.UploadClusterConfig)
+	r.GET("/api/cluster/info", handler.GetClusterInfo)
+	r.GET("/api/info", handler.GetInfo)
+	r.GET("/api/audit", adminAuth, handler.ListAuditLog)
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// 管理接口需要携带 HELM_UI_ADMIN_TOKEN 作为 Bearer token；未配置 token 时拒绝所有请求，
+	// 除非显式设置 HELM_UI_ADMIN_AUTH_DISABLED=true
+	admin := r.Group("/api/admin", adminAuth)
+	admin.POST("/cache/clear", handler.Audit("cache.clear"), handler.ClearCache)
+	admin.GET("/cache/stats", handler.GetCacheStats)
+	admin.GET("/temp", handler.ListTempFiles)
+	admin.POST("/temp/clean", handler.Audit("temp.clean"), handler.CleanTempFiles)
 
 	// 启动服务器
 	log.Fatal(http.ListenAndServe(":8081", r))
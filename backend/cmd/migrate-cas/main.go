@@ -0,0 +1,20 @@
+// migrate-cas 将按名称存储的 Chart 包转换为内容寻址存储（HELM_UI_CAS=true）使用的布局
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/smartcat999/helm-ui/internal/service"
+)
+
+func main() {
+	chartsDir := flag.String("charts-dir", "../charts", "charts directory to migrate")
+	flag.Parse()
+
+	migrated, err := service.MigrateChartsToCAS(*chartsDir)
+	if err != nil {
+		log.Fatalf("migration failed after %d charts: %v", migrated, err)
+	}
+	log.Printf("migrated %d charts to content-addressed storage", migrated)
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClearCache 清空 Chart 摘要缓存、元数据缓存与仓库索引缓存
+func (h *Handler) ClearCache(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"cleared": gin.H{
+			"chartDigests":  h.helmService.ClearDigestCache(),
+			"chartMetadata": h.helmService.ClearMetadataCache(),
+			"repoIndexes":   h.repoService.ClearIndexCache(),
+		},
+	})
+}
+
+// GetCacheStats 获取各缓存的命中统计与当前大小
+func (h *Handler) GetCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"chartDigests":  h.helmService.DigestCacheStats(),
+		"chartMetadata": h.helmService.MetadataCacheStats(),
+		"repoIndexes":   h.repoService.IndexCacheStats(),
+	})
+}
+
+// ListTempFiles 列出临时目录中的文件，stale 表示超过保留时长
+func (h *Handler) ListTempFiles(c *gin.Context) {
+	files, err := h.helmService.ListTempFiles()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"files": files})
+}
+
+// CleanTempFiles 立即清理临时目录中超过保留时长的文件
+func (h *Handler) CleanTempFiles(c *gin.Context) {
+	removed, err := h.helmService.CleanTempFiles()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"removed": removed})
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcat999/helm-ui/internal/service"
+)
+
+// AnalyzeRequest 定义渲染结果分析请求，未提供 manifests 时先渲染指定的 Chart
+type AnalyzeRequest struct {
+	Manifests string                 `json:"manifests"`
+	Chart     string                 `json:"chart"`
+	Version   string                 `json:"version"`
+	Values    map[string]interface{} `json:"values"`
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace"`
+}
+
+// AnalyzeManifests 按最佳实践规则检查 manifest，返回发现的问题及按严重程度的统计
+func (h *Handler) AnalyzeManifests(c *gin.Context) {
+	var req AnalyzeRequest
+	if !h.bindLimitedJSON(c, &req) {
+		return
+	}
+
+	manifests := req.Manifests
+	if manifests == "" {
+		if req.Chart == "" || req.Version == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Either manifests or chart and version are required"})
+			return
+		}
+
+		if req.Namespace == "" {
+			req.Namespace = h.helmService.DefaultNamespace()
+		}
+		if req.Name == "" {
+			req.Name = req.Chart
+		}
+		if err := validateReleaseTarget(req.Name, req.Namespace); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := h.helmService.RenderChartContext(c.Request.Context(), req.Chart, req.Version, req.Values, req.Name, req.Namespace, service.RenderOptions{})
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		manifests = result.Manifest
+	}
+
+	findings, err := h.helmService.AnalyzeManifests(manifests)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"findings": findings,
+		"counts":   service.CountFindings(findings),
+	})
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"archive/zip"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcat999/helm-ui/internal/service"
+)
+
+// RenderChartZip 渲染 Chart，并将每个模板文件产生的 manifest 作为单独的 .yaml 条目打包成 zip 下载
+func (h *Handler) RenderChartZip(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	req, values, opts, ok := h.bindRenderRequest(c, name, version)
+	if !ok {
+		return
+	}
+
+	result, err := h.service(c).RenderChartContext(c.Request.Context(), name, version, values, req.Name, req.Namespace, opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// 没有任何输出时返回不含条目的合法 zip
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s-%s-%s.zip", name, version, req.Name)))
+	c.Status(http.StatusOK)
+
+	zw := zip.NewWriter(c.Writer)
+	now := time.Now()
+	for _, file := range service.GroupManifestsBySource(result.Manifest) {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     zipEntryName(file.Path),
+			Method:   zip.Deflate,
+			Modified: now,
+		})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		if _, err := w.Write([]byte(file.Content)); err != nil {
+			// 响应头已发送，只能中断连接
+			c.Error(err)
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		c.Error(err)
+	}
+}
+
+// zipEntryName 将模板路径转换为 zip 条目名，路径中的目录成为 zip 中的嵌套目录，统一使用 .yaml 扩展名
+func zipEntryName(source string) string {
+	ext := path.Ext(source)
+	if ext != ".yaml" && ext != ".yml" {
+		source = strings.TrimSuffix(source, ext) + ".yaml"
+	}
+	return source
+}
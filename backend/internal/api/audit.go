@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcat999/helm-ui/internal/service"
+)
+
+const (
+	// auditTargetKey 是处理器设置审计目标时使用的 context key
+	auditTargetKey = "auditTarget"
+	// maxAuditErrorBody 捕获失败响应体的最大字节数
+	maxAuditErrorBody = 4096
+)
+
+// auditWriter 在响应失败时捕获响应体，用于提取错误信息
+type auditWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditWriter) Write(data []byte) (int, error) {
+	if w.Status() >= http.StatusBadRequest && w.body.Len() < maxAuditErrorBody {
+		w.body.Write(data[:min(len(data), maxAuditErrorBody-w.body.Len())])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Audit 返回记录变更操作的中间件，action 为操作名称（如 chart.upload）。
+// 审计目标默认取路由中的 name/version，处理器可通过 setAuditTarget 覆盖
+func (h *Handler) Audit(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := &auditWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		entry := service.AuditEntry{
+			Time:     time.Now().UTC(),
+			Identity: h.callerIdentity(c),
+			ClientIP: c.ClientIP(),
+			Action:   action,
+			Target:   auditTarget(c),
+			Method:   c.Request.Method,
+			Path:     c.Request.URL.Path,
+			Status:   writer.Status(),
+			Result:   "success",
+		}
+		if entry.Status >= http.StatusBadRequest {
+			entry.Result = "failure"
+			var body struct {
+				Error string `json:"error"`
+			}
+			if json.Unmarshal(writer.body.Bytes(), &body) == nil {
+				entry.Error = body.Error
+			}
+		}
+		h.auditLog.Record(entry)
+	}
+}
+
+// setAuditTarget 设置当前请求的审计目标
+func setAuditTarget(c *gin.Context, target string) {
+	c.Set(auditTargetKey, target)
+}
+
+// auditTarget 返回当前请求的审计目标
+func auditTarget(c *gin.Context) string {
+	if target := c.GetString(auditTargetKey); target != "" {
+		return target
+	}
+
+	var parts []string
+	for _, key := range []string{"name", "version", "profile"} {
+		if v := c.Param(key); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// callerIdentity 返回调用方身份：通过管理 token 认证的请求为 admin；
+// 否则仅在配置了可信代理用户头且请求直接来自可信代理时使用该头，其余情况为 anonymous
+func (h *Handler) callerIdentity(c *gin.Context) string {
+	if c.GetBool(adminAuthenticatedKey) {
+		return "admin"
+	}
+	if h.identity.header != "" && h.identity.fromTrustedProxy(c) {
+		if user := c.GetHeader(h.identity.header); user != "" {
+			return user
+		}
+	}
+	return "anonymous"
+}
+
+// ListAuditLog 返回最近的审计记录
+func (h *Handler) ListAuditLog(c *gin.Context) {
+	limit, ok := queryNonNegativeInt(c, "limit")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a non-negative integer"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": h.auditLog.Recent(limit)})
+}
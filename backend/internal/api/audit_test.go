@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCallerIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	trusted := identityConfig{header: "X-Remote-User", proxies: parseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})}
+
+	tests := []struct {
+		name       string
+		identity   identityConfig
+		remoteAddr string
+		user       string
+		admin      bool
+		want       string
+	}{
+		{"no header configured ignores spoofed header", identityConfig{}, "10.0.0.1:1234", "alice", false, "anonymous"},
+		{"untrusted peer ignores header", trusted, "203.0.113.5:1234", "alice", false, "anonymous"},
+		{"trusted cidr honours header", trusted, "10.1.2.3:1234", "alice", false, "alice"},
+		{"trusted single ip honours header", trusted, "192.168.1.1:1234", "bob", false, "bob"},
+		{"trusted peer without header", trusted, "10.1.2.3:1234", "", false, "anonymous"},
+		{"admin wins over proxy header", trusted, "10.1.2.3:1234", "alice", true, "admin"},
+		{"admin from untrusted peer", trusted, "203.0.113.5:1234", "alice", true, "admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+			c.Request.RemoteAddr = tt.remoteAddr
+			if tt.user != "" {
+				c.Request.Header.Set("X-Remote-User", tt.user)
+			}
+			if tt.admin {
+				c.Set(adminAuthenticatedKey, true)
+			}
+
+			h := &Handler{identity: tt.identity}
+			if got := h.callerIdentity(c); got != tt.want {
+				t.Errorf("callerIdentity() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
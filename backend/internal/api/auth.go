@@ -0,0 +1,112 @@
+package api
+
+import (
+	"crypto/subtle"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminAuthenticatedKey 标记请求携带了有效的管理 token
+const adminAuthenticatedKey = "adminAuthenticated"
+
+// AdminAuth 校验管理接口的 Bearer token（HELM_UI_ADMIN_TOKEN）。未配置 token 时默认拒绝所有请求，
+// 仅当显式设置 HELM_UI_ADMIN_AUTH_DISABLED=true 时放行，并在启动时输出警告
+func AdminAuth() gin.HandlerFunc {
+	token := os.Getenv("HELM_UI_ADMIN_TOKEN")
+	disabled := token == "" && os.Getenv("HELM_UI_ADMIN_AUTH_DISABLED") == "true"
+
+	switch {
+	case disabled:
+		log.Printf("WARNING: HELM_UI_ADMIN_AUTH_DISABLED=true, admin endpoints are open to any client")
+	case token == "":
+		log.Printf("HELM_UI_ADMIN_TOKEN is not set, admin endpoints are disabled")
+	}
+
+	return func(c *gin.Context) {
+		if disabled {
+			c.Next()
+			return
+		}
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin endpoints are disabled: HELM_UI_ADMIN_TOKEN is not set"})
+			return
+		}
+
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		c.Set(adminAuthenticatedKey, true)
+		c.Next()
+	}
+}
+
+// TrustedProxies 返回 HELM_UI_TRUSTED_PROXIES 中以逗号分隔的可信代理地址（IP 或 CIDR），未配置时为空，
+// 即不信任任何代理转发的客户端地址与用户头
+func TrustedProxies() []string {
+	var proxies []string
+	for _, p := range strings.Split(os.Getenv("HELM_UI_TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
+
+// parseTrustedProxies 将可信代理地址解析为网段，单个 IP 视为 /32 或 /128，无法解析的地址被忽略
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, p := range proxies {
+		if !strings.Contains(p, "/") {
+			if ip := net.ParseIP(p); ip != nil {
+				bits := 128
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 32
+				}
+				nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			}
+			continue
+		}
+		if _, ipNet, err := net.ParseCIDR(p); err == nil {
+			nets = append(nets, ipNet)
+		}
+	}
+	return nets
+}
+
+// identityConfig 决定审计记录中调用方身份的来源
+type identityConfig struct {
+	// header 为可信代理传递认证用户的请求头（HELM_UI_TRUSTED_PROXY_HEADER），为空时不读取任何用户头
+	header string
+	// proxies 为可信代理网段，只有直接来自这些地址的请求才读取 header
+	proxies []*net.IPNet
+}
+
+// loadIdentityConfig 从环境变量加载身份来源配置
+func loadIdentityConfig() identityConfig {
+	return identityConfig{
+		header:  os.Getenv("HELM_UI_TRUSTED_PROXY_HEADER"),
+		proxies: parseTrustedProxies(TrustedProxies()),
+	}
+}
+
+// fromTrustedProxy 判断请求是否直接来自可信代理
+func (cfg identityConfig) fromTrustedProxy(c *gin.Context) bool {
+	ip := net.ParseIP(c.RemoteIP())
+	if ip == nil {
+		return false
+	}
+	for _, n := range cfg.proxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
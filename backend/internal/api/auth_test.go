@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		token         string
+		disabled      string
+		authorization string
+		want          int
+	}{
+		{"no token configured denies", "", "", "", http.StatusForbidden},
+		{"no token configured denies any bearer", "", "", "Bearer anything", http.StatusForbidden},
+		{"explicit opt-out allows", "", "true", "", http.StatusOK},
+		{"opt-out ignored when token set", "secret", "true", "", http.StatusUnauthorized},
+		{"missing bearer", "secret", "", "", http.StatusUnauthorized},
+		{"wrong bearer", "secret", "", "Bearer wrong", http.StatusUnauthorized},
+		{"valid bearer", "secret", "", "Bearer secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HELM_UI_ADMIN_TOKEN", tt.token)
+			t.Setenv("HELM_UI_ADMIN_AUTH_DISABLED", tt.disabled)
+
+			r := gin.New()
+			r.GET("/admin", AdminAuth(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
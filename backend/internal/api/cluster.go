@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcat999/helm-ui/internal/service"
+)
+
+const (
+	// clusterSessionHeader 携带 kubeconfig 会话 token 的请求头
+	clusterSessionHeader = "X-Cluster-Session"
+	// clusterServiceKey 是会话级服务在请求上下文中的 key
+	clusterServiceKey = "clusterService"
+	// maxKubeconfigBytes 是上传 kubeconfig 的大小上限
+	maxKubeconfigBytes = 1 << 20
+)
+
+// ClusterSession 解析请求头中的会话 token，为本次请求绑定使用该会话 kubeconfig 的服务
+func (h *Handler) ClusterSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(clusterSessionHeader)
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		svc, err := h.helmService.ForClusterSession(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Set(clusterServiceKey, svc)
+		c.Next()
+	}
+}
+
+// service 返回处理本次请求应使用的服务，携带会话 token 时使用会话的 kubeconfig
+func (h *Handler) service(c *gin.Context) *service.HelmService {
+	if svc, ok := c.Get(clusterServiceKey); ok {
+		return svc.(*service.HelmService)
+	}
+	return h.helmService
+}
+
+// UploadClusterConfig 上传 kubeconfig 并创建会话，后续请求通过 X-Cluster-Session 头使用该会话
+func (h *Handler) UploadClusterConfig(c *gin.Context) {
+	// 支持 multipart 上传文件，也支持直接以请求体发送
+	var reader io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, _, err := c.Request.FormFile("kubeconfig")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No kubeconfig provided"})
+			return
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	kubeconfig, err := io.ReadAll(io.LimitReader(reader, maxKubeconfigBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read kubeconfig"})
+		return
+	}
+
+	if len(kubeconfig) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No kubeconfig provided"})
+		return
+	}
+	if len(kubeconfig) > maxKubeconfigBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Kubeconfig too large"})
+		return
+	}
+
+	session, err := h.helmService.CreateClusterSession(kubeconfig)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidKubeconfig):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrTooManySessions):
+			status = http.StatusTooManyRequests
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// GetClusterInfo 检查当前请求使用的集群配置能否连通
+func (h *Handler) GetClusterInfo(c *gin.Context) {
+	info, err := h.service(c).ClusterInfo()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
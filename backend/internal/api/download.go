@@ -0,0 +1,115 @@
+package api
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcat999/helm-ui/internal/service"
+)
+
+// DownloadChart 下载 Chart 包，Accept 为 application/x-tar 时返回解压后的 tar
+func (h *Handler) DownloadChart(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	path, err := h.helmService.ChartArchivePath(name, version)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Vary", "Accept")
+
+	// tgz 本身已经是 gzip 压缩，默认原样返回，避免重复压缩
+	if !strings.Contains(c.GetHeader("Accept"), "application/x-tar") {
+		c.Header("Content-Type", "application/gzip")
+		c.FileAttachment(path, fmt.Sprintf("%s-%s.tgz", name, version))
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to decompress chart: %v", err)})
+		return
+	}
+	defer gz.Close()
+
+	c.Header("Content-Type", "application/x-tar")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s-%s.tar", name, version)))
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, gz); err != nil {
+		// 响应头已发送，只能中断连接
+		c.Error(err)
+	}
+}
+
+// ExportCharts 以 tar.gz 流式导出所有 Chart 包及生成的 index.yaml，用于备份或迁移
+func (h *Handler) ExportCharts(c *gin.Context) {
+	filename := fmt.Sprintf("charts-export-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+	if err := h.helmService.ExportCharts(c.Writer); err != nil {
+		// 响应头已发送，只能中断连接
+		c.Error(err)
+	}
+}
+
+// ImportCharts 导入 ExportCharts 生成的归档，?overwrite=true 时覆盖已存在的版本；
+// 归档整体受 HELM_UI_MAX_IMPORT_BYTES 限制，单个 Chart 包受上传上限约束
+func (h *Handler) ImportCharts(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes())
+
+	// 支持 multipart 上传文件，也支持直接以请求体发送
+	var reader io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, _, err := c.Request.FormFile("archive")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Archive too large"})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No archive uploaded"})
+			return
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	opts := service.ImportOptions{MaxChartBytes: h.maxUploadBytes, Overwrite: c.Query("overwrite") == "true"}
+	result, err := h.helmService.ImportCharts(reader, opts)
+	if err != nil {
+		status := http.StatusInternalServerError
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			status = http.StatusRequestEntityTooLarge
+		case errors.Is(err, service.ErrInvalidChart):
+			status = http.StatusBadRequest
+		}
+		response := gin.H{"error": err.Error()}
+		// 归档中途损坏时返回已导入的部分
+		if result != nil {
+			response["result"] = result
+		}
+		c.JSON(status, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
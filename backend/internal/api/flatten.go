@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcat999/helm-ui/internal/service"
+)
+
+// FlattenValues 将合并后的 values 扁平化，format=env 时以 KEY=value 文本返回
+func (h *Handler) FlattenValues(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	var req CoalescedValuesRequest
+	if !h.bindOptionalLimitedJSON(c, &req) {
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "env" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be either json or env"})
+		return
+	}
+
+	opts := service.FlattenOptions{
+		Delimiter: c.Query("delimiter"),
+		Case:      c.Query("case"),
+	}
+	if !service.ValidFlattenCase(opts.Case) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "case must be one of upper, lower or preserve"})
+		return
+	}
+
+	values, err := h.helmService.FlattenValuesWithOptions(name, version, req.Values, opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if format == "env" {
+		c.String(http.StatusOK, service.FormatEnv(values))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"values": values})
+}
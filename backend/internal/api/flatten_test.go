@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFlattenValuesBodyLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		maxBodyBytes int64
+		body         string
+		want         int
+	}{
+		{"oversize body", 16, `{"values":{"a":"0123456789"}}`, http.StatusRequestEntityTooLarge},
+		{"too deeply nested", 1 << 20, `{"values":` + nestedJSON(maxValuesDepth) + `}`, http.StatusBadRequest},
+		{"malformed", 1 << 20, `{"values":`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{maxBodyBytes: tt.maxBodyBytes}
+			r := gin.New()
+			r.POST("/charts/:name/:version/values/flatten", h.FlattenValues)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/charts/app/0.1.0/values/flatten", strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
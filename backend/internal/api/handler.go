@@ -1,11 +1,17 @@
 package api
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"mime"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/smartcat999/helm-ui/internal/service"
@@ -14,12 +20,28 @@ import (
 // Handler 处理 API 请求
 type Handler struct {
 	helmService *service.HelmService
+	repoService *service.RepoService
+	auditLog    *service.AuditLogger
+	history     *renderHistory
+
+	// maxBodyBytes 渲染请求体的大小上限
+	maxBodyBytes int64
+	// maxUploadBytes JSON 方式上传的 Chart 包解码后的大小上限
+	maxUploadBytes int64
+	// identity 决定审计记录中调用方身份的来源
+	identity identityConfig
 }
 
 // NewHandler 创建新的处理器
-func NewHandler(helmService *service.HelmService) *Handler {
+func NewHandler(helmService *service.HelmService, repoService *service.RepoService, auditLog *service.AuditLogger) *Handler {
 	return &Handler{
-		helmService: helmService,
+		helmService:    helmService,
+		repoService:    repoService,
+		auditLog:       auditLog,
+		history:        newRenderHistory(),
+		maxBodyBytes:   maxRenderBodyBytes(),
+		maxUploadBytes: maxUploadBytes(),
+		identity:       loadIdentityConfig(),
 	}
 }
 
@@ -31,6 +53,17 @@ func (h *Handler) UploadChart(c *gin.Context) {
 		return
 	}
 	defer file.Close()
+	setAuditTarget(c, header.Filename)
+
+	// 拒绝包含链接或包外路径的 Chart 包
+	if err := service.CheckArchiveEntries(file); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read chart file"})
+		return
+	}
 
 	if err := h.helmService.UploadChart(file, header.Filename); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -40,15 +73,119 @@ func (h *Handler) UploadChart(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Chart uploaded successfully"})
 }
 
+// Base64ChartUpload 定义以 JSON 上传 Chart 包的请求
+type Base64ChartUpload struct {
+	Filename string `json:"filename"`
+	// Content 为 base64 编码的 Chart 包
+	Content string `json:"content" binding:"required"`
+}
+
+// UploadChartBase64 处理以 base64 JSON 请求体上传的 Chart 包，供无法使用 multipart 的客户端调用
+func (h *Handler) UploadChartBase64(c *gin.Context) {
+	// base64 编码后体积约为原来的 4/3，另外预留 JSON 字段的空间
+	limit := h.maxUploadBytes/3*4 + 4<<10
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+
+	var req Base64ChartUpload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Chart too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	setAuditTarget(c, req.Filename)
+
+	data, err := base64.StdEncoding.DecodeString(req.Content)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "content is not valid base64"})
+		return
+	}
+	if int64(len(data)) > h.maxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Chart too large"})
+		return
+	}
+
+	name, version, err := h.helmService.UploadChartArchive(data)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidChart) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	setAuditTarget(c, fmt.Sprintf("%s-%s.tgz", name, version))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Chart uploaded successfully", "name": name, "version": version})
+}
+
+// OCIPullRequest 定义从 OCI 仓库拉取 Chart 的请求
+type OCIPullRequest struct {
+	// Ref 形如 oci://registry.example.com/charts/app:1.0.0
+	Ref       string `json:"ref" binding:"required"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	PlainHTTP bool   `json:"plainHttp"`
+}
+
+// PullChartFromOCI 从 OCI 仓库拉取 Chart 并保存
+func (h *Handler) PullChartFromOCI(c *gin.Context) {
+	var req OCIPullRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	setAuditTarget(c, req.Ref)
+
+	opts := service.OCIPullOptions{Username: req.Username, Password: req.Password, PlainHTTP: req.PlainHTTP}
+	name, version, err := h.helmService.PullChartFromOCI(req.Ref, opts)
+	if err != nil {
+		status := errorStatus(err)
+		if errors.Is(err, service.ErrInvalidChart) {
+			status = http.StatusBadRequest
+		} else if status == http.StatusInternalServerError {
+			status = http.StatusBadGateway
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Chart pulled successfully", "name": name, "version": version})
+}
+
 // ListCharts 列出所有 Charts
 func (h *Handler) ListCharts(c *gin.Context) {
-	charts, err := h.helmService.ListCharts()
+	opts := service.ChartListOptions{
+		IncludeDeprecated: c.DefaultQuery("includeDeprecated", "true") != "false",
+		Category:          c.Query("category"),
+	}
+
+	// charts 目录没有变化时返回 304，避免重复加载与传输
+	lastModified, err := h.helmService.ChartsLastModified()
+	if err == nil {
+		lastModified = lastModified.UTC().Truncate(time.Second)
+		c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+		if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !lastModified.After(since) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	entries, err := h.helmService.ListChartEntries(opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"charts": charts})
+	// charts 保留文件名列表以兼容旧客户端，entries 附带名称、版本与废弃状态
+	charts := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		charts = append(charts, entry.Filename)
+	}
+	c.JSON(http.StatusOK, gin.H{"charts": charts, "entries": entries})
 }
 
 // ListChartVersions 列出指定 Chart 的所有版本
@@ -63,131 +200,1125 @@ func (h *Handler) ListChartVersions(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"versions": versions})
 }
 
-// GetChartValues 获取指定 Chart 的 values
+// GetChartValues 获取指定 Chart 的 values，?format= 可选 nested（默认）、flat（点号路径）或 yaml（原文）
 func (h *Handler) GetChartValues(c *gin.Context) {
 	name := c.Param("name")
 	version := c.Param("version")
 
+	format := c.DefaultQuery("format", "nested")
+	if format != "nested" && format != "flat" && format != "yaml" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of nested, flat or yaml"})
+		return
+	}
+
+	// yaml 返回 values.yaml 原文，保留注释
+	if format == "yaml" {
+		data, err := h.helmService.GetRawValues(name, version)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "application/yaml; charset=utf-8", data)
+		return
+	}
+
 	values, err := h.helmService.GetChartValues(name, version)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
+	if format == "flat" {
+		c.JSON(http.StatusOK, gin.H{"values": service.FlattenDotted(values)})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"values": values})
 }
 
-// RenderRequest 定义渲染请求的结构
-type RenderRequest struct {
-	Values        map[string]interface{} `json:"values"`
-	Name          string                 `json:"name"`
-	Namespace     string                 `json:"namespace"`
-	SelectedFiles []string               `json:"selectedFiles"`
+// PruneChartVersions 仅保留指定 Chart 最新的 keep 个版本
+func (h *Handler) PruneChartVersions(c *gin.Context) {
+	name := c.Param("name")
+
+	keep, err := strconv.Atoi(c.Query("keep"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "keep must be an integer"})
+		return
+	}
+
+	pruned, err := h.helmService.PruneChartVersions(name, keep)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pruned": pruned})
 }
 
-// RenderChart 渲染 Chart
-func (h *Handler) RenderChart(c *gin.Context) {
+// DeleteChartVersionsRequest 定义批量删除 Chart 版本的请求，versions 与 keepLatest 二选一
+type DeleteChartVersionsRequest struct {
+	Versions   []string `json:"versions"`
+	KeepLatest int      `json:"keepLatest"`
+	Force      bool     `json:"force"`
+}
+
+// DeleteChartVersions 批量删除 Chart 的多个版本，返回每个版本的删除结果
+func (h *Handler) DeleteChartVersions(c *gin.Context) {
 	name := c.Param("name")
-	version := c.Param("version")
 
-	var req RenderRequest
-	if err := c.BindJSON(&req); err != nil {
+	var req DeleteChartVersionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
 		return
 	}
+	if (len(req.Versions) > 0) == (req.KeepLatest > 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of versions or keepLatest must be provided"})
+		return
+	}
 
-	// 如果没有提供 namespace，使用 default
-	if req.Namespace == "" {
-		req.Namespace = "default"
+	result, err := h.helmService.DeleteChartVersions(name, service.DeleteVersionsOptions{
+		Versions:   req.Versions,
+		KeepLatest: req.KeepLatest,
+		Force:      req.Force,
+	})
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
 	}
 
-	// 如果没有提供 name，返回错误
-	if req.Name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Release name is required"})
+	c.JSON(http.StatusOK, result)
+}
+
+// GetChartDigest 获取 Chart 包的 SHA256 摘要
+func (h *Handler) GetChartDigest(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	digest, err := h.helmService.ChartDigest(name, version)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	result, err := h.helmService.RenderChart(name, version, req.Values, req.Name, req.Namespace, req.SelectedFiles)
+	c.JSON(http.StatusOK, gin.H{"digest": digest})
+}
+
+// GetValuesDocs 获取 values.yaml 中各 value 的文档说明
+func (h *Handler) GetValuesDocs(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	docs, err := h.helmService.GetValuesDocs(name, version)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"manifests": result})
+	c.JSON(http.StatusOK, gin.H{"docs": docs})
 }
 
-// UploadChartDir 处理 Chart 目录上传
-func (h *Handler) UploadChartDir(c *gin.Context) {
-	form, err := c.MultipartForm()
+// GetAnnotatedValues 获取带有 values.schema.json 描述和类型注释的默认 values.yaml
+func (h *Handler) GetAnnotatedValues(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	values, err := h.helmService.GetAnnotatedValues(name, version)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form data"})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	// 创建临时目录
-	tempDir, err := os.MkdirTemp("", "chart-*")
+	c.Data(http.StatusOK, "application/yaml; charset=utf-8", values)
+}
+
+// GetValuesTypes 返回根据默认 values 推断出的类型树
+func (h *Handler) GetValuesTypes(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	tree, err := h.helmService.ValuesTypeTree(name, version)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create temporary directory"})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
-	defer os.RemoveAll(tempDir)
 
-	// 获取所有上传的文件
-	files := form.File["chart"]
-	if len(files) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No files uploaded"})
+	c.JSON(http.StatusOK, tree)
+}
+
+// GetTemplateReferences 返回每个模板文件引用的命名模板
+func (h *Handler) GetTemplateReferences(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	refs, err := h.helmService.TemplateReferences(name, version)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	// 处理上传的文件
-	for _, file := range files {
-		// 从 Content-Disposition header 获取完整的文件路径
-		_, params, err := mime.ParseMediaType(file.Header.Get("Content-Disposition"))
+	c.JSON(http.StatusOK, gin.H{"references": refs})
+}
+
+// GetFullValues 返回包含所有子 Chart 默认值的完整 values
+func (h *Handler) GetFullValues(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	values, err := h.helmService.FullDefaultValues(name, version)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"values": values})
+}
+
+// CoalescedValuesRequest 定义获取合并后 values 的请求
+type CoalescedValuesRequest struct {
+	Values map[string]interface{} `json:"values"`
+}
+
+// GetCoalescedValues 获取在整个依赖树上合并默认值与覆盖值后的 values
+func (h *Handler) GetCoalescedValues(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	var req CoalescedValuesRequest
+	if !h.bindOptionalLimitedJSON(c, &req) {
+		return
+	}
+
+	values, err := h.helmService.CoalescedValues(name, version, req.Values)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"values": values})
+}
+
+// MergeProvenanceRequest 定义按顺序合并多组 values 的请求
+type MergeProvenanceRequest struct {
+	Sources []service.NamedValues `json:"sources"`
+}
+
+// chartDefaultsSource 是 Chart 默认 values 在来源中的名称
+const chartDefaultsSource = "defaults"
+
+// MergeProvenance 以 Chart 默认 values 为基础依次合并各来源，返回合并结果及每个值的来源
+func (h *Handler) MergeProvenance(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	var req MergeProvenanceRequest
+	if !h.bindLimitedJSON(c, &req) {
+		return
+	}
+
+	defaults, err := h.helmService.GetChartValues(name, version)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	sources := append([]service.NamedValues{{Name: chartDefaultsSource, Values: defaults}}, req.Sources...)
+	merged, provenance, err := h.helmService.MergeWithProvenance(sources)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"values": merged, "provenance": provenance})
+}
+
+// CheckRequiredValues 检查 values 缺失了哪些模板中 required 的字段
+func (h *Handler) CheckRequiredValues(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	var req CoalescedValuesRequest
+	if !h.bindOptionalLimitedJSON(c, &req) {
+		return
+	}
+
+	missing, err := h.helmService.CheckRequired(c.Request.Context(), name, version, req.Values)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"missing": missing})
+}
+
+// ValuesRef 定义对集群中 ConfigMap/Secret 内 values 的引用
+type ValuesRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+}
+
+// GetChartMetadata 获取指定 Chart 的元数据
+func (h *Handler) GetChartMetadata(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	metadata, err := h.helmService.GetChartMetadata(name, version)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"metadata": metadata})
+}
+
+// RenderRequest 定义渲染请求的结构
+type RenderRequest struct {
+	Values              map[string]interface{} `json:"values"`
+	Name                string                 `json:"name"`
+	Namespace           string                 `json:"namespace"`
+	SelectedFiles       []string               `json:"selectedFiles"`
+	Resources           []string               `json:"resources"`
+	DryRunMode          string                 `json:"dryRunMode"`
+	ValuesFromConfigMap *ValuesRef             `json:"valuesFromConfigMap"`
+	ValuesFromSecret    *ValuesRef             `json:"valuesFromSecret"`
+	Profile             string                 `json:"profile"`
+	Tags                map[string]bool        `json:"tags"`
+	Subcharts           map[string]bool        `json:"subcharts"`
+	NoHooks             bool                   `json:"noHooks"`
+	ArrayMergeStrategy  string                 `json:"arrayMergeStrategy"`
+	ArrayMergeKeys      map[string]string      `json:"arrayMergeKeys"`
+	Globals             map[string]interface{} `json:"globals"`
+	Debug               bool                   `json:"debug"`
+	// ChartValuesFiles 为 Chart 包内的 values 文件，按顺序合并在默认 values 之上
+	ChartValuesFiles []string `json:"chartValuesFiles"`
+	// ReleaseService 覆盖模板中的 .Release.Service，默认为 Helm
+	ReleaseService string `json:"releaseService"`
+	// InheritFrom 指定基础 Chart，其默认 values 作为最底层，目标 Chart 的默认 values 覆盖其上
+	InheritFrom *ChartRef `json:"inheritFrom"`
+	// Deterministic 冻结时间与随机函数，使重复渲染的结果一致
+	Deterministic bool `json:"deterministic"`
+}
+
+// ChartRef 引用 Chart 的某个版本
+type ChartRef struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// resolveValues 加载请求引用的基础 values，并依次合并内联 values 与查询参数中的 set 覆盖
+func (h *Handler) resolveValues(c *gin.Context, chartName, chartVersion string, req *RenderRequest) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+
+	// 优先级从低到高：基础 Chart 默认值 < 目标 Chart 默认值 < 以下各来源与内联 values
+	if ref := req.InheritFrom; ref != nil {
+		if ref.Name == "" || ref.Version == "" {
+			return nil, fmt.Errorf("%w: inheritFrom requires name and version", service.ErrInvalidValues)
+		}
+		base, err := h.helmService.InheritedValues(ref.Name, ref.Version, chartName, chartVersion)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid Content-Disposition header for file: %v", err)})
-			return
+			return nil, err
 		}
+		values = base
+	}
 
-		relativePath := params["filename"]
-		if relativePath == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "File path is missing in Content-Disposition header"})
-			return
+	if len(req.ChartValuesFiles) > 0 {
+		base, err := h.helmService.ChartValuesFiles(chartName, chartVersion, req.ChartValuesFiles)
+		if err != nil {
+			return nil, err
 		}
+		values = service.MergeValues(values, base)
+	}
 
-		// 创建目标目录
-		targetPath := filepath.Join(tempDir, relativePath)
-		targetDir := filepath.Dir(targetPath)
-		if err := os.MkdirAll(targetDir, 0755); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create directory for %s", relativePath)})
-			return
+	if ref := req.ValuesFromConfigMap; ref != nil {
+		base, err := h.service(c).ValuesFromConfigMap(refNamespace(ref, req.Namespace), ref.Name, ref.Key)
+		if err != nil {
+			return nil, err
 		}
+		values = service.MergeValues(values, base)
+	}
 
-		// 保存文件
-		if err := c.SaveUploadedFile(file, targetPath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to save file %s", relativePath)})
-			return
+	if ref := req.ValuesFromSecret; ref != nil {
+		base, err := h.service(c).ValuesFromSecret(refNamespace(ref, req.Namespace), ref.Name, ref.Key)
+		if err != nil {
+			return nil, err
 		}
+		values = service.MergeValues(values, base)
 	}
 
-	// 打包并上传 Chart
-	if err := h.helmService.UploadChartDir(tempDir); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	if req.Profile != "" {
+		base, err := h.helmService.GetProfile(chartName, req.Profile)
+		if err != nil {
+			return nil, err
+		}
+		values = service.MergeValues(values, base)
+	}
+
+	// globals 合并到 values.global 下，内联 values 中的 global 优先，helm 会将其传递给所有子 Chart
+	if len(req.Globals) > 0 {
+		values = service.MergeValues(values, map[string]interface{}{"global": req.Globals})
+	}
+
+	values = service.MergeValues(values, req.Values)
+
+	// 查询参数 ?set=key=value 可重复，优先于请求体中的 values，便于分享预览链接
+	sets, err := service.ParseSetValues(c.QueryArray("set"))
+	if err != nil {
+		return nil, err
 	}
+	return service.MergeValues(values, sets), nil
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Chart directory uploaded and packaged successfully"})
+// refNamespace 返回引用所在的命名空间，未指定时使用 release 的命名空间
+func refNamespace(ref *ValuesRef, namespace string) string {
+	if ref.Namespace != "" {
+		return ref.Namespace
+	}
+	return namespace
 }
 
-// ListChartFiles 获取指定 Chart 的文件列表
-func (h *Handler) ListChartFiles(c *gin.Context) {
+// statusClientClosedRequest 是客户端在响应前断开连接时使用的状态码（沿用 nginx 的 499）
+const statusClientClosedRequest = 499
+
+// errorStatus 根据服务层返回的错误确定 HTTP 状态码
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return statusClientClosedRequest
+	case errors.Is(err, service.ErrClusterUnavailable), errors.Is(err, service.ErrRenderQueueFull):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, service.ErrRenderTimeout):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, service.ErrValuesSourceNotFound),
+		errors.Is(err, service.ErrInvalidProfileName),
+		errors.Is(err, service.ErrInvalidPolicy),
+		errors.Is(err, service.ErrInvalidManifest),
+		errors.Is(err, service.ErrInvalidValues),
+		errors.Is(err, service.ErrInvalidSelector),
+		errors.Is(err, service.ErrInvalidRepo):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrRepoExists):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrRegistryUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrRenderFailed), errors.Is(err, service.ErrMissingDependencies):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrChartNotFound),
+		errors.Is(err, service.ErrReleaseNotFound),
+		errors.Is(err, service.ErrIconNotFound),
+		errors.Is(err, service.ErrProfileNotFound),
+		errors.Is(err, service.ErrRepoNotFound),
+		errors.Is(err, service.ErrTemplateNotFound),
+		errors.Is(err, service.ErrTemplateEmpty):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// RenderChart 渲染 Chart
+func (h *Handler) RenderChart(c *gin.Context) {
 	name := c.Param("name")
 	version := c.Param("version")
 
-	files, err := h.helmService.ListChartFiles(name, version)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	req, values, opts, ok := h.bindRenderRequest(c, name, version)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"files": files})
+	// indent 与 flowStyle 指定时重新序列化输出，否则保持 helm 的原始输出
+	format := service.FormatOptions{FlowStyle: c.Query("flowStyle")}
+	if v := c.Query("indent"); v != "" {
+		indent, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "indent must be an integer"})
+			return
+		}
+		format.Indent = indent
+	}
+	if !service.ValidFormatOptions(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "indent must be between 2 and 8 and flowStyle must be either block or flow"})
+		return
+	}
+
+	// bestEffort 模式下单个模板失败不会影响其它模板的输出
+	var (
+		result *service.RenderResult
+		err    error
+	)
+	if c.Query("bestEffort") == "true" {
+		result, err = h.service(c).RenderChartBestEffort(c.Request.Context(), name, version, values, req.Name, req.Namespace, opts)
+	} else {
+		result, err = h.service(c).RenderChartContext(c.Request.Context(), name, version, values, req.Name, req.Namespace, opts)
+	}
+	if err != nil {
+		response := gin.H{"error": err.Error()}
+		// debug 模式下附带详细错误、调试日志与部分渲染结果
+		var debugErr *service.RenderDebugError
+		if errors.As(err, &debugErr) {
+			response["debug"] = debugErr
+		}
+		c.JSON(errorStatus(err), response)
+		return
+	}
+
+	// strict 模式下选中的文件没有产生任何输出时视为错误，与 helm template --show-only 一致
+	if len(result.UnmatchedFiles) > 0 && c.Query("strict") == "true" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":          "selected files produced no manifests",
+			"unmatchedFiles": result.UnmatchedFiles,
+		})
+		return
+	}
+
+	// 模板的空白或缩进错误可能产生无法解析的 YAML，strict 模式下视为错误
+	invalidDocuments := service.ValidateManifestYAML(result.Manifest)
+	if len(invalidDocuments) > 0 && c.Query("strict") == "true" {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":            "rendered output contains invalid YAML",
+			"invalidDocuments": invalidDocuments,
+		})
+		return
+	}
+
+	if format != (service.FormatOptions{}) {
+		var warnings []string
+		result.Manifest, warnings = service.FormatManifest(result.Manifest, format)
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+
+	response := gin.H{
+		"manifests": result.Manifest,
+		"summary":   service.SummarizeManifest(result.Manifest),
+	}
+	if len(invalidDocuments) > 0 {
+		response["invalidDocuments"] = invalidDocuments
+	}
+	if len(result.UnmatchedFiles) > 0 {
+		response["unmatchedFiles"] = result.UnmatchedFiles
+	}
+	if len(result.Warnings) > 0 {
+		response["warnings"] = result.Warnings
+	}
+	if len(result.Subcharts) > 0 {
+		response["subcharts"] = result.Subcharts
+	}
+	if c.Query("bestEffort") == "true" {
+		response["errors"] = result.Errors
+	}
+	h.recordRender(c, name, version)
+	c.JSON(http.StatusOK, response)
+}
+
+// RenderChartFile 渲染 Chart 并仅返回指定模板文件产生的文档
+func (h *Handler) RenderChartFile(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	req, values, opts, ok := h.bindRenderRequest(c, name, version)
+	if !ok {
+		return
+	}
+
+	result, err := h.service(c).RenderFile(c.Request.Context(), name, version, values, req.Name, req.Namespace, opts, c.Param("path"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"manifests": result.Manifest})
+}
+
+// bindRenderRequest 解析并校验渲染请求，失败时已写入错误响应并返回 false
+func (h *Handler) bindRenderRequest(c *gin.Context, name, version string) (*RenderRequest, map[string]interface{}, service.RenderOptions, bool) {
+	var req RenderRequest
+	if !h.bindLimitedJSON(c, &req) {
+		return nil, nil, service.RenderOptions{}, false
+	}
+
+	// 如果没有提供 namespace，使用默认命名空间
+	if req.Namespace == "" {
+		req.Namespace = h.helmService.DefaultNamespace()
+	}
+
+	// 如果没有提供 name，按配置的模板生成，未配置模板时返回错误
+	if req.Name == "" {
+		generated, err := h.helmService.GenerateReleaseName(name, version, req.Namespace)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, nil, service.RenderOptions{}, false
+		}
+		if generated == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Release name is required"})
+			return nil, nil, service.RenderOptions{}, false
+		}
+		req.Name = generated
+	}
+
+	if err := validateReleaseTarget(req.Name, req.Namespace); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, nil, service.RenderOptions{}, false
+	}
+
+	values, err := h.resolveValues(c, name, version, &req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return nil, nil, service.RenderOptions{}, false
+	}
+
+	if req.DryRunMode != "" && req.DryRunMode != service.DryRunClient && req.DryRunMode != service.DryRunServer {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dryRunMode must be either client or server"})
+		return nil, nil, service.RenderOptions{}, false
+	}
+
+	if !service.ValidArrayMergeStrategy(req.ArrayMergeStrategy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "arrayMergeStrategy must be one of replace, append or merge-by-key"})
+		return nil, nil, service.RenderOptions{}, false
+	}
+
+	// 自定义 .Release.Service 需要在本地渲染，无法与 server 模式同时使用
+	if req.ReleaseService != "" && req.ReleaseService != service.DefaultReleaseService && req.DryRunMode == service.DryRunServer {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "releaseService cannot be used with server dry run"})
+		return nil, nil, service.RenderOptions{}, false
+	}
+
+	sortOrder := c.DefaultQuery("sortOrder", service.SortOrderNone)
+	if !service.ValidSortOrder(sortOrder) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sortOrder must be either install or none"})
+		return nil, nil, service.RenderOptions{}, false
+	}
+
+	opts := service.RenderOptions{
+		SelectedFiles:  req.SelectedFiles,
+		Resources:      req.Resources,
+		DryRunMode:     req.DryRunMode,
+		Tags:           req.Tags,
+		Subcharts:      req.Subcharts,
+		NoHooks:        req.NoHooks,
+		Debug:          req.Debug,
+		SortOrder:      sortOrder,
+		ReleaseService: req.ReleaseService,
+		Deterministic:  req.Deterministic,
+		ArrayMerge: service.ArrayMergeOptions{
+			Strategy: req.ArrayMergeStrategy,
+			Keys:     req.ArrayMergeKeys,
+		},
+	}
+
+	return &req, values, opts, true
+}
+
+// RegisterChartDirRequest 定义注册未打包 Chart 目录的请求
+type RegisterChartDirRequest struct {
+	Alias string `json:"alias" binding:"required"`
+	// Dir 为服务端本地的 Chart 目录
+	Dir string `json:"dir" binding:"required"`
+}
+
+// RegisterChartDir 将服务端的未打包 Chart 目录注册为 Chart，用于开发时免打包预览
+func (h *Handler) RegisterChartDir(c *gin.Context) {
+	var req RegisterChartDirRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	setAuditTarget(c, req.Alias)
+
+	if err := h.helmService.RegisterChartDir(req.Alias, req.Dir); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidChart) || errors.Is(err, service.ErrInvalidProfileName) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Chart directory registered successfully", "alias": req.Alias})
+}
+
+// UploadChartDir 处理 Chart 目录上传
+func (h *Handler) UploadChartDir(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form data"})
+		return
+	}
+
+	// 创建临时目录
+	tempDir, err := os.MkdirTemp("", "chart-*")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create temporary directory"})
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	// 获取所有上传的文件
+	files := form.File["chart"]
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No files uploaded"})
+		return
+	}
+
+	// 处理上传的文件
+	for _, file := range files {
+		// 从 Content-Disposition header 获取完整的文件路径
+		_, params, err := mime.ParseMediaType(file.Header.Get("Content-Disposition"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid Content-Disposition header for file: %v", err)})
+			return
+		}
+
+		relativePath := params["filename"]
+		if relativePath == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "File path is missing in Content-Disposition header"})
+			return
+		}
+
+		// 拒绝指向临时目录之外的路径
+		if !filepath.IsLocal(relativePath) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid file path %s", relativePath)})
+			return
+		}
+
+		// 创建目标目录
+		targetPath := filepath.Join(tempDir, relativePath)
+		targetDir := filepath.Dir(targetPath)
+		if err := os.MkdirAll(targetDir, 0755); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create directory for %s", relativePath)})
+			return
+		}
+
+		// 保存文件
+		if err := c.SaveUploadedFile(file, targetPath); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to save file %s", relativePath)})
+			return
+		}
+	}
+
+	// 按需在打包前执行 lint，strict 模式下存在错误时拒绝上传
+	var lintReport *service.LintReport
+	if c.PostForm("lint") == "true" {
+		report := h.helmService.LintChart(tempDir)
+		lintReport = &report
+
+		if c.PostForm("strict") == "true" && report.HasErrors {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Chart failed lint checks", "lint": lintReport})
+			return
+		}
+	}
+
+	// 记录被 .helmignore 排除的文件，打包时不会包含它们
+	ignoredFiles, err := h.helmService.IgnoredFiles(tempDir)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 打包并上传 Chart
+	if err := h.helmService.UploadChartDir(tempDir); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidChart) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{"message": "Chart directory uploaded and packaged successfully"}
+	if len(ignoredFiles) > 0 {
+		response["ignoredFiles"] = ignoredFiles
+	}
+	if lintReport != nil {
+		response["lint"] = lintReport
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ListChartFiles 获取指定 Chart 的文件列表，支持按前缀过滤、分页或以目录树形式返回
+func (h *Handler) ListChartFiles(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	prefix := c.Query("prefix")
+
+	// tree=true 时返回目录树，不分页
+	if c.Query("tree") == "true" {
+		tree, err := h.helmService.ChartFileTree(name, version, prefix)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"tree": tree})
+		return
+	}
+
+	opts := service.ChartFileOptions{Prefix: prefix}
+	var ok bool
+	if opts.Limit, ok = queryNonNegativeInt(c, "limit"); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a non-negative integer"})
+		return
+	}
+	if opts.Offset, ok = queryNonNegativeInt(c, "offset"); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	list, err := h.helmService.ListChartFilesPage(name, version, opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetInfo 返回服务端 Helm 版本与集群连通性信息
+func (h *Handler) GetInfo(c *gin.Context) {
+	c.JSON(http.StatusOK, h.helmService.Info())
+}
+
+// GetChartIcon 代理返回 Chart 的图标
+func (h *Handler) GetChartIcon(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	icon, err := h.helmService.GetChartIcon(name, version)
+	if err != nil {
+		// 未被识别的错误来自拉取远程图标
+		status := errorStatus(err)
+		if status == http.StatusInternalServerError {
+			status = http.StatusBadGateway
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 图标可能是包含脚本的 SVG，禁止嗅探并以沙箱方式返回，避免在 API 源下执行
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	c.Data(http.StatusOK, icon.ContentType, icon.Data)
+}
+
+// UpgradeDiffRequest 定义升级 diff 请求的结构
+type UpgradeDiffRequest struct {
+	Chart     string                 `json:"chart"`
+	Version   string                 `json:"version"`
+	Namespace string                 `json:"namespace"`
+	Values    map[string]interface{} `json:"values"`
+}
+
+// UpgradeDiff 对比 release 当前的 manifest 与升级后将要渲染的 manifest
+func (h *Handler) UpgradeDiff(c *gin.Context) {
+	releaseName := c.Param("name")
+
+	var req UpgradeDiffRequest
+	if !h.bindLimitedJSON(c, &req) {
+		return
+	}
+
+	if req.Chart == "" || req.Version == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Chart name and version are required"})
+		return
+	}
+
+	if req.Namespace == "" {
+		req.Namespace = h.helmService.DefaultNamespace()
+	}
+
+	if err := validateReleaseTarget(releaseName, req.Namespace); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	diff, err := h.service(c).UpgradeDiff(releaseName, req.Chart, req.Version, req.Namespace, req.Values)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"diff": diff, "changed": diff != ""})
+}
+
+// ClusterDiff 渲染 Chart 并与集群中的当前对象逐个对比
+func (h *Handler) ClusterDiff(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	req, values, opts, ok := h.bindRenderRequest(c, name, version)
+	if !ok {
+		return
+	}
+
+	diffs, err := h.service(c).ClusterDiff(c.Request.Context(), name, version, values, req.Name, req.Namespace, opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	changed := false
+	for _, d := range diffs {
+		if d.Action != service.DiffActionNoChange {
+			changed = true
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"resources": diffs, "changed": changed})
+}
+
+// ValuesDiffRequest 定义对比两组 values 渲染结果的请求
+type ValuesDiffRequest struct {
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace"`
+	Base      map[string]interface{} `json:"base"`
+	// Override 在 Base 之上合并
+	Override map[string]interface{} `json:"override"`
+}
+
+// DiffValuesRender 对比 values 变化前后渲染结果的差异
+func (h *Handler) DiffValuesRender(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	var req ValuesDiffRequest
+	if !h.bindLimitedJSON(c, &req) {
+		return
+	}
+
+	if req.Name == "" {
+		req.Name = name
+	}
+	if req.Namespace == "" {
+		req.Namespace = h.helmService.DefaultNamespace()
+	}
+	if err := validateReleaseTarget(req.Name, req.Namespace); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	diff, err := h.service(c).DiffValuesRender(c.Request.Context(), name, version, req.Base, req.Override, req.Name, req.Namespace)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"diff": diff, "changed": diff != ""})
+}
+
+// ListNamespaces 列出集群中的命名空间
+func (h *Handler) ListNamespaces(c *gin.Context) {
+	namespaces, err := h.service(c).ListNamespaces(c.Query("labelSelector"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"namespaces": namespaces})
+}
+
+// GetNamespaceDefaults 返回指定命名空间生效的默认 values
+func (h *Handler) GetNamespaceDefaults(c *gin.Context) {
+	namespace := c.Param("ns")
+	c.JSON(http.StatusOK, gin.H{"namespace": namespace, "values": h.helmService.NamespaceDefaults(namespace)})
+}
+
+// UpgradeImpactRequest 定义升级影响汇总请求的结构
+type UpgradeImpactRequest struct {
+	FromVersion string                 `json:"fromVersion"`
+	ToVersion   string                 `json:"toVersion"`
+	Name        string                 `json:"name"`
+	Namespace   string                 `json:"namespace"`
+	Values      map[string]interface{} `json:"values"`
+}
+
+// UpgradeImpact 汇总 Chart 两个版本之间渲染结果的资源变化
+func (h *Handler) UpgradeImpact(c *gin.Context) {
+	name := c.Param("name")
+
+	var req UpgradeImpactRequest
+	if !h.bindLimitedJSON(c, &req) {
+		return
+	}
+
+	if req.FromVersion == "" || req.ToVersion == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "fromVersion and toVersion are required"})
+		return
+	}
+
+	if req.Name == "" {
+		req.Name = name
+	}
+	if req.Namespace == "" {
+		req.Namespace = h.helmService.DefaultNamespace()
+	}
+
+	if err := validateReleaseTarget(req.Name, req.Namespace); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	summary, err := h.helmService.UpgradeImpact(name, req.FromVersion, req.ToVersion, req.Values, req.Name, req.Namespace)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// RenderAllVersionsRequest 定义渲染 Chart 所有版本的请求
+type RenderAllVersionsRequest struct {
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace"`
+	Values    map[string]interface{} `json:"values"`
+}
+
+// RenderAllVersions 使用相同的 values 渲染 Chart 的所有已存储版本
+func (h *Handler) RenderAllVersions(c *gin.Context) {
+	name := c.Param("name")
+
+	var req RenderAllVersionsRequest
+	if !h.bindLimitedJSON(c, &req) {
+		return
+	}
+
+	if req.Name == "" {
+		req.Name = name
+	}
+	if req.Namespace == "" {
+		req.Namespace = h.helmService.DefaultNamespace()
+	}
+
+	if err := validateReleaseTarget(req.Name, req.Namespace); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := h.helmService.RenderAllVersions(c.Request.Context(), name, req.Values, req.Name, req.Namespace)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": results})
+}
+
+// RenderNotesRequest 定义预览 NOTES.txt 的请求
+type RenderNotesRequest struct {
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace"`
+	Values    map[string]interface{} `json:"values"`
+}
+
+// RenderNotes 使用给定的 values 预览 Chart 的 NOTES.txt
+func (h *Handler) RenderNotes(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	var req RenderNotesRequest
+	if !h.bindLimitedJSON(c, &req) {
+		return
+	}
+
+	if req.Name == "" {
+		req.Name = name
+	}
+	if req.Namespace == "" {
+		req.Namespace = h.helmService.DefaultNamespace()
+	}
+
+	if err := validateReleaseTarget(req.Name, req.Namespace); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	notes, err := h.helmService.RenderNotes(name, version, req.Values, req.Name, req.Namespace)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notes": notes})
+}
+
+// GetDependenciesStatus 获取 Chart 依赖的打包状态
+func (h *Handler) GetDependenciesStatus(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	status, err := h.helmService.GetDependenciesStatus(name, version)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// ListChartTests 列出 Chart 中的测试 hook
+func (h *Handler) ListChartTests(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	tests, err := h.helmService.ListChartTests(name, version)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tests": tests})
+}
+
+// RunReleaseTests 运行 release 的测试并返回结果与日志
+func (h *Handler) RunReleaseTests(c *gin.Context) {
+	releaseName := c.Param("name")
+	namespace := c.DefaultQuery("namespace", h.helmService.DefaultNamespace())
+
+	if err := validateReleaseTarget(releaseName, namespace); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service(c).RunReleaseTests(releaseName, namespace)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetChartTree 返回 Chart 的依赖树
+func (h *Handler) GetChartTree(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	tree, err := h.helmService.ChartTree(name, version)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tree": tree})
+}
+
+// GetDependencyGraph 导出 Chart 的递归依赖图，format 为 json（默认）或 dot
+func (h *Handler) GetDependencyGraph(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "dot" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be either json or dot"})
+		return
+	}
+
+	graph, err := h.helmService.DependencyGraph(name, version)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if format == "dot" {
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(graph.DOT()))
+		return
+	}
+	c.JSON(http.StatusOK, graph)
 }
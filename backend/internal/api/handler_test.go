@@ -0,0 +1,342 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcat999/helm-ui/internal/service"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"render queue full", service.ErrRenderQueueFull, http.StatusServiceUnavailable},
+		{"wrapped queue full", fmt.Errorf("render: %w", service.ErrRenderQueueFull), http.StatusServiceUnavailable},
+		{"canceled while queued", context.Canceled, statusClientClosedRequest},
+		{"cluster unavailable", service.ErrClusterUnavailable, http.StatusServiceUnavailable},
+		{"render timeout", service.ErrRenderTimeout, http.StatusGatewayTimeout},
+		{"invalid values", service.ErrInvalidValues, http.StatusBadRequest},
+		{"invalid label selector", fmt.Errorf("%w: bad", service.ErrInvalidSelector), http.StatusBadRequest},
+		{"render failed", service.ErrRenderFailed, http.StatusUnprocessableEntity},
+		{"chart not found", service.ErrChartNotFound, http.StatusNotFound},
+		{"unknown", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorStatus(tt.err); got != tt.want {
+				t.Errorf("errorStatus(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveValuesGlobals(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		req  RenderRequest
+		want map[string]interface{}
+	}{
+		{
+			name: "globals merged under global",
+			req:  RenderRequest{Globals: map[string]interface{}{"foo": "bar"}},
+			want: map[string]interface{}{"global": map[string]interface{}{"foo": "bar"}},
+		},
+		{
+			name: "inline global wins and other keys are kept",
+			req: RenderRequest{
+				Globals: map[string]interface{}{"foo": "bar", "region": "eu"},
+				Values:  map[string]interface{}{"global": map[string]interface{}{"foo": "inline"}, "replicas": 2},
+			},
+			want: map[string]interface{}{"global": map[string]interface{}{"foo": "inline", "region": "eu"}, "replicas": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+
+			got, err := (&Handler{}).resolveValues(c, "app", "1.0.0", &tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// chdirTestCharts 切换到临时工作目录，使 NewHelmService 使用的 ../charts 指向空目录，返回该目录
+func chdirTestCharts(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	chartsDir := filepath.Join(root, "charts")
+	for _, dir := range []string{chartsDir, filepath.Join(root, "work")} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(root, "work")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return chartsDir
+}
+
+func TestListChartsConditional(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	chartsDir := chdirTestCharts(t)
+	h := NewHandler(service.NewHelmService(), nil, nil)
+	r := gin.New()
+	r.GET("/charts", h.ListCharts)
+
+	base := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	if err := os.Chtimes(chartsDir, base, base); err != nil {
+		t.Fatal(err)
+	}
+	modified := base.Add(30 * time.Minute)
+
+	tests := []struct {
+		name            string
+		ifModifiedSince time.Time
+		change          func(t *testing.T)
+		want            int
+	}{
+		{"unconditional", time.Time{}, nil, http.StatusOK},
+		{"unchanged since last modified", base, nil, http.StatusNotModified},
+		{"unchanged since later time", base.Add(time.Minute), nil, http.StatusNotModified},
+		{"chart file modified", base, func(t *testing.T) {
+			path := filepath.Join(chartsDir, "app-0.1.0.tgz")
+			if err := os.WriteFile(path, []byte("not a chart"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(path, modified, modified); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(chartsDir, base, base); err != nil {
+				t.Fatal(err)
+			}
+		}, http.StatusOK},
+		{"unchanged after modification", modified, nil, http.StatusNotModified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.change != nil {
+				tt.change(t)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/charts", nil)
+			if !tt.ifModifiedSince.IsZero() {
+				req.Header.Set("If-Modified-Since", tt.ifModifiedSince.Format(http.TimeFormat))
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+			if w.Header().Get("Last-Modified") == "" {
+				t.Error("Last-Modified header is missing")
+			}
+		})
+	}
+}
+
+func TestResolveValuesSetQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		query   string
+		values  map[string]interface{}
+		want    map[string]interface{}
+		wantErr error
+	}{
+		{
+			name:  "multiple set params",
+			query: "?set=image.tag=1.2.3&set=replicas=3",
+			want:  map[string]interface{}{"image": map[string]interface{}{"tag": "1.2.3"}, "replicas": int64(3)},
+		},
+		{
+			name:   "query set wins over body values",
+			query:  "?set=image.tag=1.2.3",
+			values: map[string]interface{}{"image": map[string]interface{}{"tag": "1.0.0", "repository": "nginx"}},
+			want:   map[string]interface{}{"image": map[string]interface{}{"tag": "1.2.3", "repository": "nginx"}},
+		},
+		{
+			name:    "invalid set",
+			query:   "?set=a[=1",
+			wantErr: service.ErrInvalidValues,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/"+tt.query, nil)
+
+			got, err := (&Handler{}).resolveValues(c, "app", "1.0.0", &RenderRequest{Values: tt.values})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("resolveValues() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveValues() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetChartIconHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	chartsDir := chdirTestCharts(t)
+
+	svg := `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`
+	c := &chart.Chart{Metadata: &chart.Metadata{
+		APIVersion: chart.APIVersionV2, Name: "app", Version: "0.1.0",
+		Icon: "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(svg)),
+	}}
+	if _, err := chartutil.Save(c, chartsDir); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHandler(service.NewHelmService(), nil, nil)
+	r := gin.New()
+	r.GET("/charts/:name/:version/icon", h.GetChartIcon)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/charts/app/0.1.0/icon", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"Content-Type", "image/svg+xml"},
+		{"X-Content-Type-Options", "nosniff"},
+		{"Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox"},
+	}
+	for _, tt := range tests {
+		if got := w.Header().Get(tt.header); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestRenderEndpointsClientCanceled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	chartsDir := chdirTestCharts(t)
+
+	// 模板执行一个很大的循环，不取消时渲染需要数秒；注解关闭渲染超时
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2, Name: "slow", Version: "0.1.0",
+			Annotations: map[string]string{service.RenderTimeoutAnnotation: "0s"},
+		},
+		Templates: []*chart.File{{
+			Name: "templates/cm.yaml",
+			Data: []byte("{{- range until 30000000 }}{{ end -}}\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: slow\n"),
+		}},
+	}
+	if _, err := chartutil.Save(c, chartsDir); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHandler(service.NewHelmService(), nil, nil)
+	r := gin.New()
+	r.POST("/render/:name/:version", h.RenderChart)
+	r.POST("/file/:name/:version/*path", h.RenderChartFile)
+	r.POST("/summary/:name/:version", h.RenderSummary)
+	r.POST("/documents/:name/:version", h.RenderDocuments)
+	r.POST("/zip/:name/:version", h.RenderChartZip)
+	r.POST("/diff-values/:name/:version", h.DiffValuesRender)
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"render", "/render/slow/0.1.0"},
+		{"render file", "/file/slow/0.1.0/templates/cm.yaml"},
+		{"summary", "/summary/slow/0.1.0"},
+		{"documents", "/documents/slow/0.1.0"},
+		{"zip", "/zip/slow/0.1.0"},
+		{"diff values", "/diff-values/slow/0.1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"name":"r","namespace":"default"}`)).WithContext(ctx)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			start := time.Now()
+			r.ServeHTTP(w, req)
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("handler returned after %s, want prompt return after cancel", elapsed)
+			}
+			if w.Code != statusClientClosedRequest {
+				t.Errorf("status = %d, want %d: %s", w.Code, statusClientClosedRequest, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetCoalescedValuesBodyLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		maxBodyBytes int64
+		body         string
+		want         int
+	}{
+		{"oversize body", 16, `{"values":{"a":"0123456789"}}`, http.StatusRequestEntityTooLarge},
+		{"too deeply nested", 1 << 20, `{"values":` + nestedJSON(maxValuesDepth) + `}`, http.StatusBadRequest},
+		{"too many keys", 10 << 20, `{"values":` + objectWithKeys(maxValuesKeys) + `}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{maxBodyBytes: tt.maxBodyBytes}
+			r := gin.New()
+			r.POST("/charts/:name/:version/values/coalesced", h.GetCoalescedValues)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/charts/app/0.1.0/values/coalesced", strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 渲染历史仅保存在内存中，服务重启或超过有效期后丢失，不能作为持久化存储使用
+const (
+	// defaultRenderHistorySize 是每个会话保留的最近渲染请求数
+	defaultRenderHistorySize = 20
+	// defaultRenderHistoryMaxBytes 是所有会话的渲染历史占用的总内存上限
+	defaultRenderHistoryMaxBytes = 64 << 20
+	// renderHistoryTTL 是会话最后一次使用后历史保留的时间
+	renderHistoryTTL = time.Hour
+	// renderBodyKey 是渲染请求原始请求体在 context 中的 key
+	renderBodyKey = "renderBody"
+	// renderReplayKey 标记当前请求是历史重放，不再记录
+	renderReplayKey = "renderReplay"
+)
+
+// RenderHistoryEntry 是一次成功渲染的请求
+type RenderHistoryEntry struct {
+	ID      string          `json:"id"`
+	Chart   string          `json:"chart"`
+	Version string          `json:"version"`
+	Query   string          `json:"query,omitempty"`
+	Time    time.Time       `json:"time"`
+	Request json.RawMessage `json:"request"`
+}
+
+// renderHistorySession 是单个会话的渲染历史，按时间从旧到新排列
+type renderHistorySession struct {
+	entries  []RenderHistoryEntry
+	lastUsed time.Time
+}
+
+// renderHistory 按会话保存最近的渲染请求，支持撤销时重放
+type renderHistory struct {
+	mu       sync.Mutex
+	size     int
+	maxBytes int
+	bytes    int
+	sessions map[string]*renderHistorySession
+}
+
+// newRenderHistory 创建渲染历史，容量由 HELM_UI_RENDER_HISTORY_SIZE 与 HELM_UI_RENDER_HISTORY_MAX_BYTES 配置
+func newRenderHistory() *renderHistory {
+	h := &renderHistory{
+		size:     defaultRenderHistorySize,
+		maxBytes: defaultRenderHistoryMaxBytes,
+		sessions: map[string]*renderHistorySession{},
+	}
+	if v, err := strconv.Atoi(os.Getenv("HELM_UI_RENDER_HISTORY_SIZE")); err == nil && v > 0 {
+		h.size = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("HELM_UI_RENDER_HISTORY_MAX_BYTES")); err == nil && v > 0 {
+		h.maxBytes = v
+	}
+	return h
+}
+
+// renderHistoryScope 返回请求所属的历史会话：依次使用 X-Render-Session、Authorization 与 X-Cluster-Session，
+// 以哈希值作为 key，不在内存中保存原始凭据；都没有时返回空字符串
+func renderHistoryScope(c *gin.Context) string {
+	for _, header := range []string{"X-Render-Session", "Authorization", "X-Cluster-Session"} {
+		if v := c.GetHeader(header); v != "" {
+			sum := sha256.Sum256([]byte(header + ":" + v))
+			return hex.EncodeToString(sum[:])
+		}
+	}
+	return ""
+}
+
+// record 记录一次成功的渲染，超出单个会话条数或总内存上限时淘汰最旧的记录
+func (h *renderHistory) record(scope string, entry RenderHistoryEntry) {
+	if len(entry.Request) > h.maxBytes {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	h.expire(now)
+
+	session, ok := h.sessions[scope]
+	if !ok {
+		session = &renderHistorySession{}
+		h.sessions[scope] = session
+	}
+	session.entries = append(session.entries, entry)
+	session.lastUsed = now
+	h.bytes += len(entry.Request)
+
+	for len(session.entries) > h.size {
+		h.bytes -= len(session.entries[0].Request)
+		session.entries = session.entries[1:]
+	}
+	for h.bytes > h.maxBytes {
+		h.evictOldest()
+	}
+}
+
+// expire 清理超过有效期未使用的会话
+func (h *renderHistory) expire(now time.Time) {
+	for scope, session := range h.sessions {
+		if now.Sub(session.lastUsed) > renderHistoryTTL {
+			h.drop(scope)
+		}
+	}
+}
+
+// evictOldest 淘汰所有会话中最旧的一条记录
+func (h *renderHistory) evictOldest() {
+	var oldestScope string
+	var oldest time.Time
+	for scope, session := range h.sessions {
+		if len(session.entries) == 0 {
+			continue
+		}
+		if t := session.entries[0].Time; oldestScope == "" || t.Before(oldest) {
+			oldestScope, oldest = scope, t
+		}
+	}
+	if oldestScope == "" {
+		h.bytes = 0
+		return
+	}
+
+	session := h.sessions[oldestScope]
+	h.bytes -= len(session.entries[0].Request)
+	session.entries = session.entries[1:]
+	if len(session.entries) == 0 {
+		delete(h.sessions, oldestScope)
+	}
+}
+
+// drop 删除会话及其全部记录
+func (h *renderHistory) drop(scope string) {
+	for _, entry := range h.sessions[scope].entries {
+		h.bytes -= len(entry.Request)
+	}
+	delete(h.sessions, scope)
+}
+
+// list 返回会话的渲染历史，按时间从新到旧排列
+func (h *renderHistory) list(scope string) []RenderHistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.expire(time.Now())
+	entries := []RenderHistoryEntry{}
+	if session, ok := h.sessions[scope]; ok {
+		for i := len(session.entries) - 1; i >= 0; i-- {
+			entries = append(entries, session.entries[i])
+		}
+	}
+	return entries
+}
+
+// get 返回会话中指定 ID 的记录
+func (h *renderHistory) get(scope, id string) (RenderHistoryEntry, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.expire(time.Now())
+	session, ok := h.sessions[scope]
+	if !ok {
+		return RenderHistoryEntry{}, false
+	}
+	session.lastUsed = time.Now()
+	for _, entry := range session.entries {
+		if entry.ID == id {
+			return entry, true
+		}
+	}
+	return RenderHistoryEntry{}, false
+}
+
+// recordRender 在渲染成功后将请求记录到所属会话的历史中，重放的请求与没有会话的请求不记录
+func (h *Handler) recordRender(c *gin.Context, name, version string) {
+	scope := renderHistoryScope(c)
+	if scope == "" || c.GetBool(renderReplayKey) {
+		return
+	}
+	body, ok := c.Get(renderBodyKey)
+	if !ok {
+		return
+	}
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return
+	}
+	h.history.record(scope, RenderHistoryEntry{
+		ID:      hex.EncodeToString(buf),
+		Chart:   name,
+		Version: version,
+		Query:   c.Request.URL.RawQuery,
+		Time:    time.Now().UTC(),
+		Request: json.RawMessage(body.([]byte)),
+	})
+}
+
+// ListRenderHistory 返回当前会话最近的渲染请求。历史仅保存在内存中，重启后丢失
+func (h *Handler) ListRenderHistory(c *gin.Context) {
+	scope := renderHistoryScope(c)
+	if scope == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "render history requires an X-Render-Session or Authorization header"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"history": h.history.list(scope)})
+}
+
+// ReplayRender 以历史中记录的请求重新渲染，响应与渲染接口相同
+func (h *Handler) ReplayRender(c *gin.Context) {
+	scope := renderHistoryScope(c)
+	if scope == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "render history requires an X-Render-Session or Authorization header"})
+		return
+	}
+
+	entry, ok := h.history.get(scope, c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "render history entry not found or expired"})
+		return
+	}
+
+	c.Params = gin.Params{{Key: "name", Value: entry.Chart}, {Key: "version", Value: entry.Version}}
+	c.Request.URL.RawQuery = entry.Query
+	c.Request.Body = io.NopCloser(bytes.NewReader(entry.Request))
+	c.Set(renderReplayKey, true)
+	h.RenderChart(c)
+}
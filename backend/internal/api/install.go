@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcat999/helm-ui/internal/service"
+)
+
+// InstallChartUpload 以 multipart 表单上传 values 文件并安装 Chart，
+// 多个 values 文件按上传顺序合并，后面的优先
+func (h *Handler) InstallChartUpload(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	// 整个表单受渲染请求体大小上限约束
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBodyBytes)
+	if err := c.Request.ParseMultipartForm(h.maxBodyBytes); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid multipart form"})
+		return
+	}
+	// 较大的 values 文件会被写入临时文件，请求结束（包括被取消）时清理
+	defer c.Request.MultipartForm.RemoveAll()
+
+	releaseName := c.PostForm("name")
+	namespace := c.DefaultPostForm("namespace", h.helmService.DefaultNamespace())
+	if err := validateReleaseTarget(releaseName, namespace); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	setAuditTarget(c, namespace+"/"+releaseName)
+
+	// atomic 安装失败时自动卸载 release，noHooks 跳过 Chart 的 hook
+	var opts service.InstallOptions
+	for _, field := range []struct {
+		name   string
+		target *bool
+	}{{"atomic", &opts.Atomic}, {"noHooks", &opts.NoHooks}} {
+		v, err := formBool(c, field.name)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		*field.target = v
+	}
+
+	// 字段名写错时不应静默使用默认 values 安装
+	files := c.Request.MultipartForm.File["values"]
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No values file uploaded"})
+		return
+	}
+
+	// 先校验所有 values 文件，再访问集群
+	values := map[string]interface{}{}
+	for _, header := range files {
+		file, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read values file"})
+			return
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read values file"})
+			return
+		}
+
+		fileValues, err := service.ParseValuesFile(data, header.Filename)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		values = service.MergeValues(values, fileValues)
+	}
+
+	result, err := h.service(c).InstallChart(c.Request.Context(), name, version, values, releaseName, namespace, opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// formBool 解析表单中的布尔字段，字段为空时返回 false
+func formBool(c *gin.Context, name string) (bool, error) {
+	value := c.PostForm(name)
+	if value == "" {
+		return false, nil
+	}
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("Invalid %s value", name)
+	}
+	return v, nil
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFormBool(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		form    url.Values
+		want    bool
+		wantErr bool
+	}{
+		{"missing", url.Values{}, false, false},
+		{"true", url.Values{"noHooks": {"true"}}, true, false},
+		{"numeric", url.Values{"noHooks": {"1"}}, true, false},
+		{"false", url.Values{"noHooks": {"false"}}, false, false},
+		{"invalid", url.Values{"noHooks": {"maybe"}}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.form.Encode()))
+			c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			got, err := formBool(c, "noHooks")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("formBool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("formBool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultMaxRenderBodyBytes 是渲染请求体的默认大小上限
+	defaultMaxRenderBodyBytes = 5 << 20
+	// defaultMaxUploadBytes 是 JSON 方式上传的 Chart 包解码后的默认大小上限
+	defaultMaxUploadBytes = 50 << 20
+	// defaultMaxImportBytes 是导入归档的默认大小上限
+	defaultMaxImportBytes = 1 << 30
+	// maxValuesDepth 是请求 JSON 允许的最大嵌套深度
+	maxValuesDepth = 64
+	// maxValuesKeys 是请求 JSON 中允许的对象 key 总数
+	maxValuesKeys = 100000
+)
+
+// errJSONTooComplex 表示请求 JSON 的嵌套深度或 key 数量超出限制
+var errJSONTooComplex = errors.New("request JSON is too deeply nested or has too many keys")
+
+// maxRenderBodyBytes 读取 HELM_UI_MAX_RENDER_BODY_BYTES，未设置或非法时使用默认值
+func maxRenderBodyBytes() int64 {
+	if v, err := strconv.ParseInt(os.Getenv("HELM_UI_MAX_RENDER_BODY_BYTES"), 10, 64); err == nil && v > 0 {
+		return v
+	}
+	return defaultMaxRenderBodyBytes
+}
+
+// maxUploadBytes 读取 HELM_UI_MAX_UPLOAD_BYTES，未设置或非法时使用默认值
+func maxUploadBytes() int64 {
+	if v, err := strconv.ParseInt(os.Getenv("HELM_UI_MAX_UPLOAD_BYTES"), 10, 64); err == nil && v > 0 {
+		return v
+	}
+	return defaultMaxUploadBytes
+}
+
+// maxImportBytes 读取 HELM_UI_MAX_IMPORT_BYTES，未设置或非法时使用默认值
+func maxImportBytes() int64 {
+	if v, err := strconv.ParseInt(os.Getenv("HELM_UI_MAX_IMPORT_BYTES"), 10, 64); err == nil && v > 0 {
+		return v
+	}
+	return defaultMaxImportBytes
+}
+
+// bindLimitedJSON 在限制请求体大小、嵌套深度和 key 数量的前提下解析 JSON，
+// 失败时已写入错误响应并返回 false
+func (h *Handler) bindLimitedJSON(c *gin.Context, obj interface{}) bool {
+	return h.bindLimitedJSONBody(c, obj, false)
+}
+
+// bindOptionalLimitedJSON 与 bindLimitedJSON 相同，但允许请求体为空，此时 obj 保持不变
+func (h *Handler) bindOptionalLimitedJSON(c *gin.Context, obj interface{}) bool {
+	return h.bindLimitedJSONBody(c, obj, true)
+}
+
+// bindLimitedJSONBody 实现 bindLimitedJSON，optional 为 true 时空请求体视为成功
+func (h *Handler) bindLimitedJSONBody(c *gin.Context, obj interface{}, optional bool) bool {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return false
+	}
+	if optional && len(bytes.TrimSpace(body)) == 0 {
+		return true
+	}
+
+	if err := checkJSONComplexity(body, maxValuesDepth, maxValuesKeys); err != nil {
+		if errors.Is(err, errJSONTooComplex) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return false
+	}
+
+	if err := json.Unmarshal(body, obj); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return false
+	}
+	c.Set(renderBodyKey, body)
+	return true
+}
+
+// checkJSONComplexity 以流式方式扫描 JSON，在真正解析前拒绝嵌套过深或 key 过多的输入
+func checkJSONComplexity(data []byte, maxDepth, maxKeys int) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	// 记录每一层是否为对象，以及对象中下一个 token 是否为 key
+	type frame struct {
+		object    bool
+		expectKey bool
+	}
+	var stack []frame
+	keys := 0
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// 对象中的 key
+		if n := len(stack); n > 0 && stack[n-1].object && stack[n-1].expectKey {
+			if _, ok := tok.(string); ok {
+				stack[n-1].expectKey = false
+				keys++
+				if keys > maxKeys {
+					return errJSONTooComplex
+				}
+				continue
+			}
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			stack = append(stack, frame{object: tok == json.Delim('{'), expectKey: tok == json.Delim('{')})
+			if len(stack) > maxDepth {
+				return errJSONTooComplex
+			}
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+		}
+
+		// 一个值结束后，所在对象的下一个 token 为 key
+		if n := len(stack); n > 0 && stack[n-1].object {
+			stack[n-1].expectKey = true
+		}
+	}
+}
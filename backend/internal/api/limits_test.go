@@ -0,0 +1,136 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// nestedJSON 返回嵌套 depth 层对象的 JSON
+func nestedJSON(depth int) string {
+	return strings.Repeat(`{"a":`, depth) + "1" + strings.Repeat("}", depth)
+}
+
+// objectWithKeys 返回包含 n 个 key 的 JSON 对象
+func objectWithKeys(n int) string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf(`"k%d":%d`, i, i)
+	}
+	return "{" + strings.Join(keys, ",") + "}"
+}
+
+func TestCheckJSONComplexity(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		maxDepth int
+		maxKeys  int
+		wantErr  error
+	}{
+		{"flat object", `{"a":1,"b":"x"}`, 4, 10, nil},
+		{"depth at limit", nestedJSON(4), 4, 10, nil},
+		{"depth over limit", nestedJSON(5), 4, 10, errJSONTooComplex},
+		{"arrays count towards depth", `[[[[[1]]]]]`, 4, 10, errJSONTooComplex},
+		{"keys at limit", objectWithKeys(10), 4, 10, nil},
+		{"keys over limit", objectWithKeys(11), 4, 10, errJSONTooComplex},
+		{"keys counted across nested objects", `{"a":{"b":1,"c":2},"d":[{"e":1}]}`, 4, 4, errJSONTooComplex},
+		{"string values are not keys", `{"a":["b","c","d","e"]}`, 4, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkJSONComplexity([]byte(tt.input), tt.maxDepth, tt.maxKeys)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkJSONComplexity() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBindLimitedJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		maxBodyBytes int64
+		body         string
+		want         int
+	}{
+		{"valid body", 1 << 20, `{"values":{"a":1}}`, http.StatusOK},
+		{"oversize body", 16, `{"values":{"a":"0123456789"}}`, http.StatusRequestEntityTooLarge},
+		{"too deeply nested", 1 << 20, `{"values":` + nestedJSON(maxValuesDepth) + `}`, http.StatusBadRequest},
+		{"too many keys", 10 << 20, `{"values":` + objectWithKeys(maxValuesKeys) + `}`, http.StatusBadRequest},
+		{"malformed", 1 << 20, `{"values":`, http.StatusBadRequest},
+		{"wrong type", 1 << 20, `{"values":[1]}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{maxBodyBytes: tt.maxBodyBytes}
+			r := gin.New()
+			r.POST("/", func(c *gin.Context) {
+				var req struct {
+					Values map[string]interface{} `json:"values"`
+				}
+				if !h.bindLimitedJSON(c, &req) {
+					return
+				}
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBindOptionalLimitedJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		optional bool
+		body     string
+		want     int
+	}{
+		{"empty optional body", true, "", http.StatusOK},
+		{"blank optional body", true, " \n", http.StatusOK},
+		{"empty required body", false, "", http.StatusBadRequest},
+		{"optional body still limited", true, `{"values":` + nestedJSON(maxValuesDepth) + `}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{maxBodyBytes: 1 << 20}
+			r := gin.New()
+			r.POST("/", func(c *gin.Context) {
+				var req struct {
+					Values map[string]interface{} `json:"values"`
+				}
+				bind := h.bindLimitedJSON
+				if tt.optional {
+					bind = h.bindOptionalLimitedJSON
+				}
+				if !bind(c, &req) {
+					return
+				}
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
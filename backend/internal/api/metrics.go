@@ -0,0 +1,78 @@
+package api
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/smartcat999/helm-ui/internal/service"
+)
+
+// maxMetricCharts 限制按 Chart 统计的指标中不同 chart 标签的数量，超出的 Chart 计入 "other"
+const maxMetricCharts = 100
+
+// RegisterMetrics 注册服务相关的 Prometheus 指标；HELM_UI_DETAILED_METRICS=true 时额外按 Chart 统计渲染次数与耗时
+func RegisterMetrics(helmService *service.HelmService) {
+	prometheus.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "helm_ui_renders_in_flight",
+			Help: "Number of chart renders currently executing.",
+		}, func() float64 {
+			inFlight, _ := helmService.RenderStats()
+			return float64(inFlight)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "helm_ui_renders_queued",
+			Help: "Number of chart renders waiting for a free slot.",
+		}, func() float64 {
+			_, queued := helmService.RenderStats()
+			return float64(queued)
+		}),
+	)
+
+	if os.Getenv("HELM_UI_DETAILED_METRICS") == "true" {
+		registerChartMetrics(helmService)
+	}
+}
+
+// registerChartMetrics 注册按 Chart 统计的渲染指标
+func registerChartMetrics(helmService *service.HelmService) {
+	renders := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "helm_ui_renders_total",
+		Help: "Number of chart renders by chart, version and status.",
+	}, []string{"chart", "version", "status"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "helm_ui_render_duration_seconds",
+		Help:    "Duration of chart renders by chart.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"chart"})
+	prometheus.MustRegister(renders, duration)
+
+	labels := &chartLabels{seen: map[string]bool{}}
+	helmService.SetRenderObserver(func(chart, version, status string, d time.Duration) {
+		chart, version = labels.label(chart, version)
+		renders.WithLabelValues(chart, version, status).Inc()
+		duration.WithLabelValues(chart).Observe(d.Seconds())
+	})
+}
+
+// chartLabels 记录已出现的 chart 标签，用于限制指标的基数
+type chartLabels struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// label 返回指标使用的 chart 与 version 标签，不同 Chart 超过上限后新出现的 Chart 统一为 "other"
+func (l *chartLabels) label(chart, version string) (string, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.seen[chart] {
+		if len(l.seen) >= maxMetricCharts {
+			return "other", "other"
+		}
+		l.seen[chart] = true
+	}
+	return chart, version
+}
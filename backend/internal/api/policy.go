@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcat999/helm-ui/internal/service"
+)
+
+// PolicyEvaluateRequest 定义策略评估请求，未提供 manifests 时先渲染指定的 Chart
+type PolicyEvaluateRequest struct {
+	Manifests string                 `json:"manifests"`
+	Chart     string                 `json:"chart"`
+	Version   string                 `json:"version"`
+	Values    map[string]interface{} `json:"values"`
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace"`
+	Policies  []string               `json:"policies"`
+}
+
+// EvaluatePolicy 使用 Rego 策略评估 manifest，存在违规时返回 422
+func (h *Handler) EvaluatePolicy(c *gin.Context) {
+	var req PolicyEvaluateRequest
+	if !h.bindLimitedJSON(c, &req) {
+		return
+	}
+
+	if len(req.Policies) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one policy is required"})
+		return
+	}
+
+	manifests := req.Manifests
+	if manifests == "" {
+		if req.Chart == "" || req.Version == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Either manifests or chart and version are required"})
+			return
+		}
+
+		if req.Namespace == "" {
+			req.Namespace = h.helmService.DefaultNamespace()
+		}
+		if req.Name == "" {
+			req.Name = req.Chart
+		}
+		if err := validateReleaseTarget(req.Name, req.Namespace); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := h.helmService.RenderChartContext(c.Request.Context(), req.Chart, req.Version, req.Values, req.Name, req.Namespace, service.RenderOptions{})
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		manifests = result.Manifest
+	}
+
+	results, err := h.helmService.EvaluatePolicy(manifests, req.Policies)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	violations := []service.PolicyResult{}
+	warnings := []service.PolicyResult{}
+	for _, r := range results {
+		if r.Denied() {
+			violations = append(violations, r)
+		} else {
+			warnings = append(warnings, r)
+		}
+	}
+
+	status := http.StatusOK
+	if len(violations) > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, gin.H{
+		"passed":     len(violations) == 0,
+		"violations": violations,
+		"warnings":   warnings,
+	})
+}
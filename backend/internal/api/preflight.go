@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Preflight 在安装前执行 lint、schema、必填字段与 dry-run 渲染检查，
+// 检查未通过时仍返回 200，结果见报告中的 ok 字段
+func (h *Handler) Preflight(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	req, values, opts, ok := h.bindRenderRequest(c, name, version)
+	if !ok {
+		return
+	}
+
+	report, err := h.service(c).Preflight(c.Request.Context(), name, version, values, req.Name, req.Namespace, opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
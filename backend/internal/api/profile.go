@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SaveProfile 保存 Chart 的命名 values profile
+func (h *Handler) SaveProfile(c *gin.Context) {
+	name := c.Param("name")
+	profile := c.Param("profile")
+
+	var values map[string]interface{}
+	if !h.bindLimitedJSON(c, &values) {
+		return
+	}
+
+	if err := h.helmService.SaveProfile(name, profile, values); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Profile saved successfully"})
+}
+
+// GetProfile 获取 Chart 的命名 values profile
+func (h *Handler) GetProfile(c *gin.Context) {
+	name := c.Param("name")
+	profile := c.Param("profile")
+
+	values, err := h.helmService.GetProfile(name, profile)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"profile": profile, "values": values})
+}
+
+// DeleteProfile 删除 Chart 的命名 values profile
+func (h *Handler) DeleteProfile(c *gin.Context) {
+	name := c.Param("name")
+	profile := c.Param("profile")
+
+	if err := h.helmService.DeleteProfile(name, profile); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Profile deleted successfully"})
+}
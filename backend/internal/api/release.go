@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcat999/helm-ui/internal/service"
+)
+
+// ListReleases 分页列出 release，支持按命名空间、名称过滤及排序
+func (h *Handler) ListReleases(c *gin.Context) {
+	opts := service.ReleaseListOptions{
+		Namespace: c.Query("namespace"),
+		Filter:    c.Query("filter"),
+		Sort:      c.DefaultQuery("sort", service.ReleaseSortUpdated),
+	}
+
+	if opts.Namespace != "" {
+		if err := validateNamespace(opts.Namespace); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if opts.Sort != service.ReleaseSortUpdated && opts.Sort != service.ReleaseSortName {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be either name or updated"})
+		return
+	}
+
+	var ok bool
+	if opts.Limit, ok = queryNonNegativeInt(c, "limit"); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a non-negative integer"})
+		return
+	}
+	if opts.Offset, ok = queryNonNegativeInt(c, "offset"); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	list, err := h.service(c).ListReleases(opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// queryNonNegativeInt 解析非负整数查询参数，未提供时返回 0
+func queryNonNegativeInt(c *gin.Context, key string) (int, bool) {
+	v := c.Query(key)
+	if v == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// GetReleaseManifest 获取 release 指定版本部署的 manifest
+func (h *Handler) GetReleaseManifest(c *gin.Context) {
+	releaseName := c.Param("name")
+	namespace := c.DefaultQuery("namespace", h.helmService.DefaultNamespace())
+
+	if err := validateReleaseTarget(releaseName, namespace); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	revision, ok := queryNonNegativeInt(c, "revision")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "revision must be a non-negative integer"})
+		return
+	}
+
+	manifest, err := h.service(c).GetReleaseManifest(releaseName, namespace, revision)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/yaml; charset=utf-8", []byte(manifest))
+}
+
+// GetReleaseResources 列出 release 管理的集群对象及其当前状态
+func (h *Handler) GetReleaseResources(c *gin.Context) {
+	releaseName := c.Param("name")
+	namespace := c.DefaultQuery("namespace", h.helmService.DefaultNamespace())
+
+	if err := validateReleaseTarget(releaseName, namespace); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resources, err := h.service(c).GetReleaseResources(releaseName, namespace)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resources": resources})
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcat999/helm-ui/internal/service"
+)
+
+// ListRepos 列出已配置的 Helm 仓库
+func (h *Handler) ListRepos(c *gin.Context) {
+	repos, err := h.repoService.ListRepos()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"repos": repos})
+}
+
+// AddRepoRequest 定义添加仓库的请求
+type AddRepoRequest struct {
+	Name     string `json:"name" binding:"required"`
+	URL      string `json:"url" binding:"required"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// TLSCert、TLSKey 与 TLSCA 为 PEM 格式的内容
+	TLSCert               string `json:"tlsCert"`
+	TLSKey                string `json:"tlsKey"`
+	TLSCA                 string `json:"tlsCA"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify"`
+}
+
+// AddRepo 添加 Helm 仓库
+func (h *Handler) AddRepo(c *gin.Context) {
+	var req AddRepoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	setAuditTarget(c, req.Name)
+
+	auth := service.RepoAuth{
+		Username:              req.Username,
+		Password:              req.Password,
+		CertPEM:               req.TLSCert,
+		KeyPEM:                req.TLSKey,
+		CAPEM:                 req.TLSCA,
+		InsecureSkipTLSVerify: req.InsecureSkipTLSVerify,
+	}
+	if err := h.repoService.AddRepo(req.Name, req.URL, auth); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Repository added successfully"})
+}
+
+// RemoveRepo 删除 Helm 仓库
+func (h *Handler) RemoveRepo(c *gin.Context) {
+	setAuditTarget(c, c.Param("name"))
+	if err := h.repoService.RemoveRepo(c.Param("name")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Repository removed successfully"})
+}
+
+// RefreshRepo 立即刷新指定仓库的索引
+func (h *Handler) RefreshRepo(c *gin.Context) {
+	if err := h.repoService.RefreshRepo(c.Param("name")); err != nil {
+		status := errorStatus(err)
+		if status == http.StatusInternalServerError {
+			status = http.StatusBadGateway
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Repository index refreshed successfully"})
+}
+
+// CheckForUpdates 检查已配置的仓库中是否存在比本地更新的 Chart 版本
+func (h *Handler) CheckForUpdates(c *gin.Context) {
+	name := c.Param("name")
+	current, latest, updateAvailable, err := h.repoService.CheckForUpdates(name)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chart":           name,
+		"current":         current,
+		"latest":          latest,
+		"updateAvailable": updateAvailable,
+	})
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamFlushEvery 每写出多少个文档刷新一次响应
+const streamFlushEvery = 10
+
+// RenderChartStream 以纯文本流的形式逐个输出渲染后的 manifest 文档
+func (h *Handler) RenderChartStream(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	req, values, opts, ok := h.bindRenderRequest(c, name, version)
+	if !ok {
+		return
+	}
+
+	// 渲染错误通过 trailer 和结尾的哨兵行告知客户端
+	c.Header("Trailer", "X-Render-Error")
+
+	started := false
+	count := 0
+	err := h.service(c).RenderChartStream(c.Request.Context(), name, version, values, req.Name, req.Namespace, opts, func(doc string) error {
+		if !started {
+			c.Header("Content-Type", "text/plain; charset=utf-8")
+			c.Status(http.StatusOK)
+			started = true
+		}
+
+		if _, err := fmt.Fprintf(c.Writer, "---\n%s\n", doc); err != nil {
+			return err
+		}
+
+		count++
+		if count%streamFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+
+	if err != nil {
+		// 尚未输出任何内容时仍可返回正常的错误状态码
+		if !started {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		fmt.Fprintf(c.Writer, "# ERROR: %s\n", err.Error())
+		c.Writer.Header().Set("X-Render-Error", err.Error())
+		return
+	}
+
+	if !started {
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+		c.Status(http.StatusOK)
+	}
+	c.Writer.Flush()
+}
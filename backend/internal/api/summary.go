@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RenderSummary 渲染 Chart 并返回生成的资源列表，hook 资源单独标记
+func (h *Handler) RenderSummary(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	req, values, opts, ok := h.bindRenderRequest(c, name, version)
+	if !ok {
+		return
+	}
+
+	resources, err := h.service(c).RenderSummary(c.Request.Context(), name, version, values, req.Name, req.Namespace, opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resources": resources})
+}
+
+// RenderDocuments 渲染 Chart 并按文档返回 manifest 及其 kind、名称等元数据
+func (h *Handler) RenderDocuments(c *gin.Context) {
+	name := c.Param("name")
+	version := c.Param("version")
+
+	req, values, opts, ok := h.bindRenderRequest(c, name, version)
+	if !ok {
+		return
+	}
+
+	documents, err := h.service(c).RenderDocuments(c.Request.Context(), name, version, values, req.Name, req.Namespace, opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"documents": documents})
+}
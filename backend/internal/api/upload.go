@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcat999/helm-ui/internal/service"
+)
+
+// InitChartUpload 创建分片上传
+func (h *Handler) InitChartUpload(c *gin.Context) {
+	id, err := h.helmService.InitUpload()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"uploadId": id, "offset": 0})
+}
+
+// GetChartUpload 返回分片上传已接收的字节数
+func (h *Handler) GetChartUpload(c *gin.Context) {
+	offset, err := h.helmService.UploadOffset(c.Param("id"))
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"uploadId": c.Param("id"), "offset": offset})
+}
+
+// PatchChartUpload 接收一个分片，通过 Content-Range 指定其在文件中的位置
+func (h *Handler) PatchChartUpload(c *gin.Context) {
+	offset, err := parseContentRangeStart(c.GetHeader("Content-Range"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 单个分片与整个上传都不得超过 HELM_UI_MAX_UPLOAD_BYTES
+	if c.Request.ContentLength > 0 && offset+c.Request.ContentLength > h.maxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Chart too large"})
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadBytes)
+
+	size, err := h.helmService.WriteUploadChunk(c.Param("id"), offset, c.Request.Body, h.maxUploadBytes)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error(), "offset": size})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"uploadId": c.Param("id"), "offset": size})
+}
+
+// CompleteChartUpload 组装并校验分片上传的 Chart 包
+func (h *Handler) CompleteChartUpload(c *gin.Context) {
+	setAuditTarget(c, "upload/"+c.Param("id"))
+	filename, err := h.helmService.CompleteUpload(c.Param("id"))
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	setAuditTarget(c, filename)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Chart uploaded successfully", "chart": filename})
+}
+
+// uploadErrorStatus 根据分片上传的错误确定 HTTP 状态码
+func uploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUploadNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrUploadOffsetMismatch):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrUploadTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, service.ErrInvalidChart):
+		return http.StatusBadRequest
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusInternalServerError
+}
+
+// parseContentRangeStart 解析 "bytes start-end/total" 形式的 Content-Range，返回起始偏移
+func parseContentRangeStart(header string) (int64, error) {
+	if header == "" {
+		return 0, errors.New("Content-Range header is required")
+	}
+
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, fmt.Errorf("invalid Content-Range header: %s", header)
+	}
+
+	rangePart, _, _ := strings.Cut(spec, "/")
+	startPart, _, ok := strings.Cut(rangePart, "-")
+	if !ok {
+		return 0, fmt.Errorf("invalid Content-Range header: %s", header)
+	}
+
+	start, err := strconv.ParseInt(startPart, 10, 64)
+	if err != nil || start < 0 {
+		return 0, fmt.Errorf("invalid Content-Range header: %s", header)
+	}
+
+	return start, nil
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// maxReleaseNameLength 是 helm 允许的 release 名称最大长度
+const maxReleaseNameLength = 53
+
+// validateReleaseName 校验 release 名称符合 DNS-1123 子域名规则且不超过 helm 的长度限制
+func validateReleaseName(name string) error {
+	if len(name) > maxReleaseNameLength {
+		return fmt.Errorf("invalid release name %q: must be no more than %d characters", name, maxReleaseNameLength)
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("invalid release name %q: %s", name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// validateNamespace 校验命名空间符合 DNS-1123 标签规则
+func validateNamespace(namespace string) error {
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace %q: %s", namespace, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// validateReleaseTarget 校验 release 名称与目标命名空间
+func validateReleaseTarget(releaseName, namespace string) error {
+	if err := validateReleaseName(releaseName); err != nil {
+		return err
+	}
+	return validateNamespace(namespace)
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateReleaseName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"simple", "my-release", false},
+		{"dots and digits", "web.v2-1", false},
+		{"exactly 53 characters", strings.Repeat("a", 53), false},
+		{"54 characters", strings.Repeat("a", 54), true},
+		{"empty", "", true},
+		{"uppercase", "MyRelease", true},
+		{"leading dash", "-release", true},
+		{"trailing dash", "release-", true},
+		{"underscore", "my_release", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateReleaseName(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateReleaseName(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"simple", "default", false},
+		{"with dash", "team-a", false},
+		{"exactly 63 characters", strings.Repeat("a", 63), false},
+		{"64 characters", strings.Repeat("a", 64), true},
+		{"empty", "", true},
+		{"uppercase", "Default", true},
+		{"leading dash", "-team", true},
+		{"dots are not allowed in labels", "team.a", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNamespace(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateNamespace(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateReleaseTarget(t *testing.T) {
+	if err := validateReleaseTarget("web", "default"); err != nil {
+		t.Errorf("validateReleaseTarget() error = %v", err)
+	}
+	if err := validateReleaseTarget("web", "Bad"); err == nil {
+		t.Error("validateReleaseTarget() accepted an invalid namespace")
+	}
+	if err := validateReleaseTarget("", "default"); err == nil {
+		t.Error("validateReleaseTarget() accepted an empty release name")
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// 分析结果的严重程度
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Finding 描述渲染结果中的一个问题及其所在位置
+type Finding struct {
+	// Rule 为触发的规则名称
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	// Resource 为 Kind/name 形式的资源标识
+	Resource   string `json:"resource"`
+	SourceFile string `json:"sourceFile,omitempty"`
+	// Path 为问题在资源中的字段路径，如 spec.template.spec.containers[0]
+	Path string `json:"path,omitempty"`
+}
+
+// AnalyzerRule 是一条渲染结果分析规则，对单个资源返回发现的问题；
+// AnalyzeManifests 会补全 Rule、Resource 与 SourceFile，Severity 为空时视为 warning
+type AnalyzerRule interface {
+	Name() string
+	Check(obj map[string]interface{}) []Finding
+}
+
+// RegisterAnalyzerRule 注册额外的分析规则，在内置规则之后按注册顺序执行
+func (s *HelmService) RegisterAnalyzerRule(rule AnalyzerRule) {
+	s.analyzerRulesMu.Lock()
+	defer s.analyzerRulesMu.Unlock()
+	s.analyzerRules = append(s.analyzerRules, rule)
+}
+
+// builtinAnalyzerRules 返回内置的 Kubernetes 最佳实践规则
+func builtinAnalyzerRules() []AnalyzerRule {
+	return []AnalyzerRule{
+		resourceLimitsRule{},
+		latestImageTagRule{},
+		probesRule{},
+		privilegedRule{},
+	}
+}
+
+// AnalyzeManifests 使用内置与已注册的规则检查渲染后的 manifest，返回按资源顺序排列的问题；
+// 文档不是合法的 YAML 时返回 ErrInvalidManifest
+func (s *HelmService) AnalyzeManifests(manifests string) ([]Finding, error) {
+	s.analyzerRulesMu.RLock()
+	rules := append(builtinAnalyzerRules(), s.analyzerRules...)
+	s.analyzerRulesMu.RUnlock()
+
+	findings := []Finding{}
+	for _, doc := range splitManifests(manifests) {
+		if isEmptyManifest(doc) {
+			continue
+		}
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+		}
+		if obj == nil {
+			continue
+		}
+
+		head := parseManifestHead(doc)
+		resource := head.Kind + "/" + head.Metadata.Name
+		source := manifestSource(doc)
+		for _, rule := range rules {
+			for _, f := range rule.Check(obj) {
+				f.Rule = rule.Name()
+				f.Resource = resource
+				f.SourceFile = source
+				if f.Severity == "" {
+					f.Severity = SeverityWarning
+				}
+				findings = append(findings, f)
+			}
+		}
+	}
+	return findings, nil
+}
+
+// podContainer 是工作负载 Pod 模板中的一个容器
+type podContainer struct {
+	path string
+	spec map[string]interface{}
+	init bool
+}
+
+// podSpecPaths 记录各工作负载类型中 Pod spec 所在的字段路径
+var podSpecPaths = map[string][]string{
+	"Pod":                   {"spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// podContainers 返回工作负载中的容器与初始化容器，非工作负载资源返回空
+func podContainers(obj map[string]interface{}) []podContainer {
+	kind, _ := obj["kind"].(string)
+	path, ok := podSpecPaths[kind]
+	if !ok {
+		return nil
+	}
+
+	spec := obj
+	for _, key := range path {
+		next, ok := spec[key].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		spec = next
+	}
+
+	var containers []podContainer
+	for _, field := range []string{"initContainers", "containers"} {
+		list, _ := spec[field].([]interface{})
+		for i, item := range list {
+			if c, ok := item.(map[string]interface{}); ok {
+				containers = append(containers, podContainer{
+					path: fmt.Sprintf("%s.%s[%d]", strings.Join(path, "."), field, i),
+					spec: c,
+					init: field == "initContainers",
+				})
+			}
+		}
+	}
+	return containers
+}
+
+// containerName 返回容器名称，用于问题描述
+func containerName(c podContainer) string {
+	name, _ := c.spec["name"].(string)
+	return name
+}
+
+// resourceLimitsRule 检查容器是否设置了 CPU 与内存限制
+type resourceLimitsRule struct{}
+
+// Name 实现 AnalyzerRule
+func (resourceLimitsRule) Name() string { return "resource-limits" }
+
+// Check 实现 AnalyzerRule
+func (resourceLimitsRule) Check(obj map[string]interface{}) []Finding {
+	var findings []Finding
+	for _, c := range podContainers(obj) {
+		resources, _ := c.spec["resources"].(map[string]interface{})
+		limits, _ := resources["limits"].(map[string]interface{})
+		var missing []string
+		for _, r := range []string{"cpu", "memory"} {
+			if _, ok := limits[r]; !ok {
+				missing = append(missing, r)
+			}
+		}
+		if len(missing) > 0 {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("container %q has no %s limit", containerName(c), strings.Join(missing, " or ")),
+				Path:     c.path + ".resources.limits",
+			})
+		}
+	}
+	return findings
+}
+
+// latestImageTagRule 检查容器镜像是否使用 latest 标签或未指定标签
+type latestImageTagRule struct{}
+
+// Name 实现 AnalyzerRule
+func (latestImageTagRule) Name() string { return "latest-image-tag" }
+
+// Check 实现 AnalyzerRule
+func (latestImageTagRule) Check(obj map[string]interface{}) []Finding {
+	var findings []Finding
+	for _, c := range podContainers(obj) {
+		image, _ := c.spec["image"].(string)
+		if image == "" || strings.Contains(image, "@") {
+			continue
+		}
+		// 仓库地址可能带端口，标签只出现在最后一段
+		last := image[strings.LastIndex(image, "/")+1:]
+		_, tag, ok := strings.Cut(last, ":")
+		if !ok || tag == "latest" {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("container %q uses image %q without a pinned tag", containerName(c), image),
+				Path:     c.path + ".image",
+			})
+		}
+	}
+	return findings
+}
+
+// probesRule 检查长期运行的容器是否配置了存活与就绪探针，Job 与 CronJob 不检查
+type probesRule struct{}
+
+// Name 实现 AnalyzerRule
+func (probesRule) Name() string { return "probes" }
+
+// Check 实现 AnalyzerRule
+func (probesRule) Check(obj map[string]interface{}) []Finding {
+	if kind, _ := obj["kind"].(string); kind == "Job" || kind == "CronJob" {
+		return nil
+	}
+
+	var findings []Finding
+	for _, c := range podContainers(obj) {
+		if c.init {
+			continue
+		}
+		var missing []string
+		for _, probe := range []string{"livenessProbe", "readinessProbe"} {
+			if _, ok := c.spec[probe]; !ok {
+				missing = append(missing, probe)
+			}
+		}
+		if len(missing) > 0 {
+			findings = append(findings, Finding{
+				Severity: SeverityInfo,
+				Message:  fmt.Sprintf("container %q has no %s", containerName(c), strings.Join(missing, " or ")),
+				Path:     c.path,
+			})
+		}
+	}
+	return findings
+}
+
+// privilegedRule 检查容器是否以特权模式运行
+type privilegedRule struct{}
+
+// Name 实现 AnalyzerRule
+func (privilegedRule) Name() string { return "privileged" }
+
+// Check 实现 AnalyzerRule
+func (privilegedRule) Check(obj map[string]interface{}) []Finding {
+	var findings []Finding
+	for _, c := range podContainers(obj) {
+		securityContext, _ := c.spec["securityContext"].(map[string]interface{})
+		if privileged, _ := securityContext["privileged"].(bool); privileged {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("container %q runs in privileged mode", containerName(c)),
+				Path:     c.path + ".securityContext.privileged",
+			})
+		}
+	}
+	return findings
+}
+
+// CountFindings 按严重程度统计问题数量
+func CountFindings(findings []Finding) map[string]int {
+	counts := map[string]int{SeverityError: 0, SeverityWarning: 0, SeverityInfo: 0}
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	return counts
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// compliantContainer 是不会触发任何内置规则的容器
+const compliantContainer = `
+      - name: app
+        image: nginx:1.25
+        resources:
+          limits:
+            cpu: 100m
+            memory: 128Mi
+        livenessProbe:
+          httpGet: {path: /, port: 80}
+        readinessProbe:
+          httpGet: {path: /, port: 80}`
+
+func TestAnalyzeManifestsBuiltinRules(t *testing.T) {
+	deployment := func(containers string) string {
+		return "# Source: app/templates/deploy.yaml\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  template:\n    spec:\n      containers:" + containers
+	}
+
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+	}{
+		{"compliant", deployment(compliantContainer), nil},
+		{"not a workload", "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\n", nil},
+		{"missing limits", deployment(`
+      - name: app
+        image: nginx:1.25
+        resources:
+          limits:
+            cpu: 100m
+        livenessProbe: {exec: {command: [true]}}
+        readinessProbe: {exec: {command: [true]}}`), []string{"resource-limits/warning/spec.template.spec.containers[0].resources.limits"}},
+		{"latest and untagged images", deployment(compliantContainer + `
+      - name: sidecar
+        image: registry.example.com:5000/proxy
+        resources: {limits: {cpu: 1, memory: 1Gi}}
+        livenessProbe: {exec: {command: [true]}}
+        readinessProbe: {exec: {command: [true]}}
+      - name: tool
+        image: busybox:latest
+        resources: {limits: {cpu: 1, memory: 1Gi}}
+        livenessProbe: {exec: {command: [true]}}
+        readinessProbe: {exec: {command: [true]}}
+      - name: pinned
+        image: busybox@sha256:abc
+        resources: {limits: {cpu: 1, memory: 1Gi}}
+        livenessProbe: {exec: {command: [true]}}
+        readinessProbe: {exec: {command: [true]}}`), []string{
+			"latest-image-tag/warning/spec.template.spec.containers[1].image",
+			"latest-image-tag/warning/spec.template.spec.containers[2].image",
+		}},
+		{"missing probes", deployment(`
+      - name: app
+        image: nginx:1.25
+        resources: {limits: {cpu: 1, memory: 1Gi}}`), []string{"probes/info/spec.template.spec.containers[0]"}},
+		{"jobs and init containers need no probes", `apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: nightly
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          initContainers:
+          - name: init
+            image: busybox:1.36
+            resources: {limits: {cpu: 1, memory: 1Gi}}
+          containers:
+          - name: job
+            image: busybox:1.36
+            resources: {limits: {cpu: 1, memory: 1Gi}}`, nil},
+		{"privileged", `apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:` + compliantContainer + `
+        securityContext:
+          privileged: true`, []string{"privileged/error/spec.containers[0].securityContext.privileged"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := (&HelmService{}).AnalyzeManifests(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range findings {
+				got = append(got, f.Rule+"/"+f.Severity+"/"+f.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// replicasRule 是测试用的自定义规则，不设置严重程度
+type replicasRule struct{}
+
+func (replicasRule) Name() string { return "single-replica" }
+
+func (replicasRule) Check(obj map[string]interface{}) []Finding {
+	spec, _ := obj["spec"].(map[string]interface{})
+	if replicas, ok := spec["replicas"].(float64); ok && replicas < 2 {
+		return []Finding{{Message: "only one replica", Path: "spec.replicas"}}
+	}
+	return nil
+}
+
+func TestAnalyzeManifestsCustomRule(t *testing.T) {
+	s := &HelmService{}
+	s.RegisterAnalyzerRule(replicasRule{})
+
+	manifest := "---\n# Source: app/templates/deploy.yaml\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replicas: 1\n  template:\n    spec:\n      containers:" + compliantContainer + "\n---\n# Source: app/templates/empty.yaml\n"
+	findings, err := s.AnalyzeManifests(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 规则名称、资源与来源文件由 AnalyzeManifests 补全，未设置的严重程度视为 warning
+	want := []Finding{{
+		Rule: "single-replica", Severity: SeverityWarning, Message: "only one replica",
+		Resource: "Deployment/web", SourceFile: "app/templates/deploy.yaml", Path: "spec.replicas",
+	}}
+	if !reflect.DeepEqual(findings, want) {
+		t.Errorf("findings = %+v, want %+v", findings, want)
+	}
+}
+
+func TestAnalyzeManifestsInvalid(t *testing.T) {
+	if _, err := (&HelmService{}).AnalyzeManifests("kind: [unclosed"); !errors.Is(err, ErrInvalidManifest) {
+		t.Errorf("AnalyzeManifests() error = %v, want %v", err, ErrInvalidManifest)
+	}
+}
+
+func TestCountFindings(t *testing.T) {
+	tests := []struct {
+		name     string
+		findings []Finding
+		want     map[string]int
+	}{
+		{"none", nil, map[string]int{SeverityError: 0, SeverityWarning: 0, SeverityInfo: 0}},
+		{"mixed", []Finding{{Severity: SeverityError}, {Severity: SeverityInfo}, {Severity: SeverityInfo}}, map[string]int{SeverityError: 1, SeverityWarning: 0, SeverityInfo: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CountFindings(tt.findings); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CountFindings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// auditBufferSize 是内存中保留的最近审计记录数
+const auditBufferSize = 1000
+
+// AuditEntry 是一条变更操作的审计记录
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	Identity string    `json:"identity"`
+	ClientIP string    `json:"clientIP"`
+	Action   string    `json:"action"`
+	Target   string    `json:"target"`
+	Method   string    `json:"method"`
+	Path     string    `json:"path"`
+	Status   int       `json:"status"`
+	// Result 为 success 或 failure
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// AuditLogger 记录变更操作：以 JSON Lines 写入配置的输出，并在内存中保留最近的记录供查询
+type AuditLogger struct {
+	mu      sync.Mutex
+	sink    io.Writer
+	entries []AuditEntry
+}
+
+// NewAuditLogger 创建审计日志，sink 为 stdout 时写入标准输出，为文件路径时追加写入该文件，
+// 为空时仅在内存中保留记录。写入文件时会加载文件中已有的最近记录
+func NewAuditLogger(sink string) (*AuditLogger, error) {
+	a := &AuditLogger{}
+
+	switch sink {
+	case "":
+	case "stdout":
+		a.sink = os.Stdout
+	default:
+		a.entries = loadAuditEntries(sink)
+
+		f, err := os.OpenFile(sink, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+		a.sink = f
+	}
+
+	return a, nil
+}
+
+// loadAuditEntries 读取审计日志文件中最近的记录，无法解析的行被忽略
+func loadAuditEntries(path string) []AuditEntry {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+		if len(entries) > auditBufferSize {
+			entries = entries[1:]
+		}
+	}
+	return entries
+}
+
+// Record 记录一条审计日志，写入失败只记录错误日志，不影响被审计的操作
+func (a *AuditLogger) Record(entry AuditEntry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.entries = append(a.entries, entry)
+	if len(a.entries) > auditBufferSize {
+		a.entries = a.entries[len(a.entries)-auditBufferSize:]
+	}
+
+	if a.sink == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("failed to encode audit entry: %v", err)
+		return
+	}
+	if _, err := a.sink.Write(append(data, '\n')); err != nil {
+		log.Printf("failed to write audit entry for %s %s: %v", entry.Action, entry.Target, err)
+	}
+}
+
+// Recent 返回最近的 limit 条审计记录，按时间从新到旧排列，limit 为 0 时返回全部
+func (a *AuditLogger) Recent(limit int) []AuditEntry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if limit <= 0 || limit > len(a.entries) {
+		limit = len(a.entries)
+	}
+
+	result := make([]AuditEntry, 0, limit)
+	for i := len(a.entries) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, a.entries[i])
+	}
+	return result
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+// TemplateError 描述单个模板的渲染错误
+type TemplateError struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+// RenderChartBestEffort 渲染 Chart，整体渲染失败时逐个模板单独渲染，
+// 返回渲染成功的 manifest，失败模板的错误记录在 RenderResult.Errors 中。
+//
+// 逐个渲染与 helm 整体渲染的“全有或全无”行为存在细微差异：该模式始终在本地渲染，不会执行 server 模式的 dry-run，
+// 且模板之间无法通过共享状态相互影响。确定性渲染、.Release.Service 覆盖、渲染超时与 ctx 取消的处理与 RenderChartContext 一致
+func (s *HelmService) RenderChartBestEffort(ctx context.Context, name, version string, values map[string]interface{}, releaseName, namespace string, opts RenderOptions) (*RenderResult, error) {
+	result, err := s.RenderChartContext(ctx, name, version, values, releaseName, namespace, opts)
+	if err == nil {
+		return result, nil
+	}
+
+	// 只有模板渲染错误才能通过逐个渲染恢复
+	if !errors.Is(err, ErrRenderFailed) {
+		return nil, err
+	}
+
+	if err := s.renderLimiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	// 超时被放弃的渲染在后台结束后才释放名额
+	abandoned := false
+	defer func() {
+		if !abandoned {
+			s.renderLimiter.release()
+		}
+	}()
+
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Deterministic {
+		freezeChartTemplates(chart)
+	}
+
+	declared := declaredSubcharts(chart)
+	warnings := append(chartWarnings(chart), valueTypeWarnings(chart, values)...)
+	valuesToRender, err := s.renderValues(chart, values, releaseName, namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// templateErrors 在渲染结束、结果送出前写入，调用方只在收到结果后读取
+	var templateErrors []TemplateError
+	rel, abandoned, err := s.runRenderWithTimeout(ctx, chart, func() (*release.Release, error) {
+		var rel *release.Release
+		var err error
+		rel, templateErrors, err = renderEachTemplate(chart, valuesToRender)
+		return rel, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := rel.Manifest
+	if !opts.NoHooks {
+		manifest = appendHooks(manifest, rel.Hooks)
+	}
+
+	return &RenderResult{
+		Manifest:       sortManifests(filterManifests(manifest, chart.Metadata.Name, opts.SelectedFiles, opts.Resources), opts.SortOrder),
+		Warnings:       warnings,
+		Errors:         templateErrors,
+		Subcharts:      subchartStatus(chart, declared),
+		UnmatchedFiles: unmatchedFiles(manifest, chart.Metadata.Name, opts.SelectedFiles),
+	}, nil
+}
+
+// renderEachTemplate 逐个渲染 Chart 中的模板，返回由渲染成功的模板组成的 release 与失败模板的错误
+func renderEachTemplate(chart *chart.Chart, valuesToRender chartutil.Values) (*release.Release, []TemplateError, error) {
+	caps := chartutil.DefaultCapabilities
+
+	rendered := map[string]string{}
+	var templateErrors []TemplateError
+	for _, tpl := range renderableTemplates(chart) {
+		files, err := engine.Render(isolateTemplate(chart, tpl), valuesToRender)
+		if err != nil {
+			templateErrors = append(templateErrors, TemplateError{File: tpl, Error: err.Error()})
+			continue
+		}
+
+		// NOTES.txt 不是 manifest，需在解析前去掉
+		manifests := map[string]string{}
+		for file, content := range files {
+			if strings.TrimSpace(content) != "" && !strings.HasSuffix(file, "NOTES.txt") {
+				manifests[file] = content
+			}
+		}
+		// 提前解析一次，确保生成的是合法的 manifest
+		if _, _, err := releaseutil.SortManifests(manifests, caps.APIVersions, releaseutil.InstallOrder); err != nil {
+			templateErrors = append(templateErrors, TemplateError{File: tpl, Error: err.Error()})
+			continue
+		}
+		for file, content := range manifests {
+			rendered[file] = content
+		}
+	}
+
+	hooks, manifests, err := releaseutil.SortManifests(rendered, caps.APIVersions, releaseutil.InstallOrder)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sort manifests: %w", err)
+	}
+
+	var b strings.Builder
+	for _, m := range manifests {
+		fmt.Fprintf(&b, "---\n# Source: %s\n%s\n", m.Name, m.Content)
+	}
+	return &release.Release{Manifest: b.String(), Hooks: hooks}, templateErrors, nil
+}
+
+// renderValues 按与 renderRelease 相同的方式准备 values，并生成模板引擎使用的渲染上下文
+func (s *HelmService) renderValues(chart *chart.Chart, values map[string]interface{}, releaseName, namespace string, opts RenderOptions) (chartutil.Values, error) {
+	values, err := s.prepareValues(chart.Metadata.Name, namespace, values)
+	if err != nil {
+		return nil, err
+	}
+	values = applyArrayMerge(chart, values, opts.ArrayMerge)
+	values = MergeValues(values, applySubchartToggles(chart, opts.Tags, opts.Subcharts))
+	if err := chartutil.ProcessDependenciesWithMerge(chart, values); err != nil {
+		return nil, fmt.Errorf("failed to process dependencies: %w", err)
+	}
+
+	options := chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	valuesToRender, err := chartutil.ToRenderValues(chart, values, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare render values: %w", err)
+	}
+	if rel, ok := valuesToRender["Release"].(map[string]interface{}); ok && opts.ReleaseService != "" {
+		rel["Service"] = opts.ReleaseService
+	}
+	return valuesToRender, nil
+}
+
+// renderableTemplates 返回 Chart 及其子 Chart 中所有会产生输出的模板路径，
+// library Chart 只提供 helper 模板，与 helm 一致不渲染其中的模板
+func renderableTemplates(c *chart.Chart) []string {
+	var templates []string
+	for _, tpl := range c.Templates {
+		if !strings.HasPrefix(path.Base(tpl.Name), "_") && !isLibraryChart(c) {
+			templates = append(templates, path.Join(c.ChartFullPath(), tpl.Name))
+		}
+	}
+	for _, dep := range c.Dependencies() {
+		templates = append(templates, renderableTemplates(dep)...)
+	}
+	sort.Strings(templates)
+	return templates
+}
+
+// isLibraryChart 判断 Chart 是否为 library 类型
+func isLibraryChart(c *chart.Chart) bool {
+	return strings.EqualFold(c.Metadata.Type, "library")
+}
+
+// isolateTemplate 复制 Chart 树，仅保留 helper 模板以及指定的模板
+func isolateTemplate(c *chart.Chart, target string) *chart.Chart {
+	return isolateTemplateWithPath(c, target, c.Name())
+}
+
+// isolateTemplateWithPath 按 Chart 在树中的完整路径复制并过滤模板
+func isolateTemplateWithPath(c *chart.Chart, target, fullPath string) *chart.Chart {
+	cp := *c
+	cp.Templates = nil
+	for _, tpl := range c.Templates {
+		if strings.HasPrefix(path.Base(tpl.Name), "_") || path.Join(fullPath, tpl.Name) == target {
+			cp.Templates = append(cp.Templates, tpl)
+		}
+	}
+
+	var deps []*chart.Chart
+	for _, dep := range c.Dependencies() {
+		deps = append(deps, isolateTemplateWithPath(dep, target, fullPath+"/charts/"+dep.Name()))
+	}
+	cp.SetDependencies(deps...)
+
+	return &cp
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// libraryAppChart 返回依赖 library Chart 的应用 Chart，failing 为 true 时额外包含一个渲染失败的模板
+func libraryAppChart(name string, failing bool) *chart.Chart {
+	lib := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "lib", Version: "1.0.0", Type: "library"},
+		Templates: []*chart.File{
+			{Name: "templates/_cm.tpl", Data: []byte(`{{- define "lib.configmap" -}}
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-from-lib
+{{- end -}}`)},
+			// library Chart 中的普通模板不会被 helm 渲染
+			{Name: "templates/unused.yaml", Data: []byte(`{{ fail "library templates must not be rendered" }}`)},
+		},
+	}
+	app := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2, Name: name, Version: "0.1.0",
+			Dependencies: []*chart.Dependency{{Name: "lib", Version: "1.0.0"}},
+		},
+		Templates: []*chart.File{{Name: "templates/cm.yaml", Data: []byte(`{{ include "lib.configmap" . }}`)}},
+	}
+	if failing {
+		app.Templates = append(app.Templates, &chart.File{Name: "templates/fail.yaml", Data: []byte(`{{ fail "boom" }}`)})
+	}
+	app.SetDependencies(lib)
+	return app
+}
+
+func TestRenderChartBestEffortLibraryChart(t *testing.T) {
+	s := newRenderTestService(t, libraryAppChart("app", false), libraryAppChart("broken", true))
+
+	tests := []struct {
+		name       string
+		chart      string
+		wantErrors []string
+	}{
+		{"full render", "app", nil},
+		{"per-template fallback", "broken", []string{"broken/templates/fail.yaml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.RenderChartBestEffort(context.Background(), tt.chart, "0.1.0", nil, "r", "default", RenderOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(result.Manifest, "name: r-from-lib") {
+				t.Errorf("manifest does not use the library template:\n%s", result.Manifest)
+			}
+			var failed []string
+			for _, e := range result.Errors {
+				failed = append(failed, e.File)
+			}
+			if !reflect.DeepEqual(failed, tt.wantErrors) {
+				t.Errorf("failed templates = %v, want %v", failed, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestRenderableTemplates(t *testing.T) {
+	tests := []struct {
+		name  string
+		chart *chart.Chart
+		want  []string
+	}{
+		{"library subchart skipped", libraryAppChart("app", false), []string{"app/templates/cm.yaml"}},
+		{"helpers skipped", &chart.Chart{
+			Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "app", Version: "0.1.0"},
+			Templates: []*chart.File{
+				{Name: "templates/b.yaml"},
+				{Name: "templates/_helpers.tpl"},
+				{Name: "templates/a.yaml"},
+			},
+		}, []string{"app/templates/a.yaml", "app/templates/b.yaml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderableTemplates(tt.chart); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("renderableTemplates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderChartBestEffortErrors(t *testing.T) {
+	file := func(name, data string) *chart.File {
+		return &chart.File{Name: "templates/" + name, Data: []byte(data)}
+	}
+	cm := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: ok\n"
+
+	tests := []struct {
+		name       string
+		templates  []*chart.File
+		wantErrors []string
+	}{
+		{"execution error", []*chart.File{file("ok.yaml", cm), file("fail.yaml", `{{ fail "boom" }}`)}, []string{"app/templates/fail.yaml"}},
+		{"NOTES.txt is not a manifest", []*chart.File{file("ok.yaml", cm), file("NOTES.txt", "Installed {{ .Release.Name }}"), file("fail.yaml", `{{ fail "boom" }}`)}, []string{"app/templates/fail.yaml"}},
+		{"invalid yaml", []*chart.File{file("ok.yaml", cm), file("bad.yaml", "kind: [unclosed\n"), file("fail.yaml", `{{ fail "boom" }}`)}, []string{"app/templates/bad.yaml", "app/templates/fail.yaml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newRenderTestService(t, &chart.Chart{
+				Metadata:  &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "app", Version: "0.1.0"},
+				Templates: tt.templates,
+			})
+
+			result, err := s.RenderChartBestEffort(context.Background(), "app", "0.1.0", nil, "r", "default", RenderOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var failed []string
+			for _, e := range result.Errors {
+				failed = append(failed, e.File)
+			}
+			if !reflect.DeepEqual(failed, tt.wantErrors) {
+				t.Errorf("failed templates = %v, want %v", failed, tt.wantErrors)
+			}
+			if !strings.Contains(result.Manifest, "name: ok") {
+				t.Errorf("manifest is missing the valid template:\n%s", result.Manifest)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+const (
+	// casBlobDir 是内容寻址存储中 Chart 包所在的子目录
+	casBlobDir = "sha256"
+	// casIndexFile 是 name/version 到摘要映射的索引文件
+	casIndexFile = "cas-index.json"
+)
+
+// casStore 内容寻址存储：Chart 包按内容摘要保存为 sha256/<digest>.tgz，
+// name/version 到摘要的映射单独保存在索引文件中
+type casStore struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+// casIndex 是索引文件的内容：Chart 名称 -> 版本 -> 摘要
+type casIndex map[string]map[string]string
+
+// casEntry 描述索引中的一个 Chart 版本
+type casEntry struct {
+	name    string
+	version string
+	digest  string
+}
+
+// newCASStore 创建以 dir 为根目录的内容寻址存储
+func newCASStore(dir string) *casStore {
+	return &casStore{dir: dir}
+}
+
+// blobPath 返回摘要对应的 Chart 包路径
+func (c *casStore) blobPath(digest string) string {
+	return filepath.Join(c.dir, casBlobDir, digest+".tgz")
+}
+
+// load 读取索引文件，文件不存在时返回空索引
+func (c *casStore) load() (casIndex, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, casIndexFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return casIndex{}, nil
+		}
+		return nil, fmt.Errorf("failed to read chart index: %w", err)
+	}
+
+	index := casIndex{}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse chart index: %w", err)
+	}
+	return index, nil
+}
+
+// save 原子地写入索引文件
+func (c *casStore) save(index casIndex) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode chart index: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, casIndexFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write chart index: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write chart index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write chart index: %w", err)
+	}
+	return os.Rename(tmp.Name(), filepath.Join(c.dir, casIndexFile))
+}
+
+// resolve 返回 name/version 对应的摘要
+func (c *casStore) resolve(name, version string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	index, err := c.load()
+	if err != nil {
+		log.Printf("failed to resolve chart %s-%s: %v", name, version, err)
+		return "", false
+	}
+	digest, ok := index[name][version]
+	return digest, ok
+}
+
+// entries 返回索引中的所有 Chart 版本，按名称和版本排序
+func (c *casStore) entries() ([]casEntry, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	index, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []casEntry
+	for name, versions := range index {
+		for version, digest := range versions {
+			entries = append(entries, casEntry{name: name, version: version, digest: digest})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].name != entries[j].name {
+			return entries[i].name < entries[j].name
+		}
+		return entries[i].version < entries[j].version
+	})
+	return entries, nil
+}
+
+// put 按内容摘要保存 Chart 包并更新索引，返回包路径；内容相同的包只保存一份
+func (c *casStore) put(src io.Reader) (string, error) {
+	blobDir := filepath.Join(c.dir, casBlobDir)
+	if err := os.MkdirAll(blobDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create charts directory: %w", err)
+	}
+
+	// 先写入临时文件，同时计算摘要
+	tmp, err := os.CreateTemp(blobDir, "upload-*.tgz")
+	if err != nil {
+		return "", fmt.Errorf("failed to create chart file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), src); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to copy chart file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write chart file: %w", err)
+	}
+
+	chart, err := loader.Load(tmp.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to load chart: %w", err)
+	}
+
+	digest := hex.EncodeToString(hash.Sum(nil))
+	path := c.blobPath(digest)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	index, err := c.load()
+	if err != nil {
+		return "", err
+	}
+
+	// 已存在相同内容的包时直接复用
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			return "", fmt.Errorf("failed to store chart file: %w", err)
+		}
+	}
+
+	name, version := chart.Metadata.Name, chart.Metadata.Version
+	if previous, ok := index[name][version]; ok && previous != digest {
+		log.Printf("chart %s-%s replaced: sha256:%s -> sha256:%s", name, version, previous, digest)
+	}
+	if index[name] == nil {
+		index[name] = map[string]string{}
+	}
+	index[name][version] = digest
+
+	if err := c.save(index); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// remove 从索引中删除 name/version，并删除不再被引用的包
+func (c *casStore) remove(name, version string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	index, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	digest, ok := index[name][version]
+	if !ok {
+		return nil
+	}
+	delete(index[name], version)
+	if len(index[name]) == 0 {
+		delete(index, name)
+	}
+	if err := c.save(index); err != nil {
+		return err
+	}
+
+	for _, versions := range index {
+		for _, d := range versions {
+			if d == digest {
+				return nil
+			}
+		}
+	}
+	if err := os.Remove(c.blobPath(digest)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove chart file: %w", err)
+	}
+	return nil
+}
+
+// listChartsCAS 以 <name>-<version>.tgz 的形式列出内容寻址存储中的 Chart
+func (s *HelmService) listChartsCAS(opts ChartListOptions) ([]listedChart, error) {
+	entries, err := s.cas.entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var charts []listedChart
+	for _, entry := range entries {
+		path := s.cas.blobPath(entry.digest)
+		if !s.includeArchive(path, opts) {
+			continue
+		}
+		version, _ := semver.NewVersion(entry.version)
+		listed := listedChart{
+			filename:   fmt.Sprintf("%s-%s.tgz", entry.name, entry.version),
+			name:       entry.name,
+			version:    version,
+			rawVersion: entry.version,
+		}
+		if metadata, err := s.archiveMetadata(path); err == nil {
+			listed.deprecated = metadata.Deprecated
+		}
+		charts = append(charts, listed)
+	}
+	orderListedCharts(charts)
+	return charts, nil
+}
+
+// MigrateChartsToCAS 将 chartsDir 下按名称存储的 Chart 包转换为内容寻址存储，返回迁移的包数
+func MigrateChartsToCAS(chartsDir string) (int, error) {
+	files, err := os.ReadDir(chartsDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read charts directory: %w", err)
+	}
+
+	store := newCASStore(chartsDir)
+	migrated := 0
+	for _, file := range files {
+		if file.IsDir() || !isChartArchive(file.Name()) {
+			continue
+		}
+
+		path := filepath.Join(chartsDir, file.Name())
+		f, err := os.Open(path)
+		if err != nil {
+			return migrated, fmt.Errorf("failed to open %s: %w", file.Name(), err)
+		}
+		stored, err := store.put(f)
+		f.Close()
+		if err != nil {
+			return migrated, fmt.Errorf("failed to migrate %s: %w", file.Name(), err)
+		}
+
+		if err := os.Remove(path); err != nil {
+			return migrated, fmt.Errorf("failed to remove %s: %w", file.Name(), err)
+		}
+		log.Printf("migrated %s -> %s", file.Name(), stored)
+		migrated++
+	}
+
+	return migrated, nil
+}
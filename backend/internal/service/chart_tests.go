@@ -0,0 +1,157 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// releaseTestTimeout 是运行 release 测试的超时时间
+const releaseTestTimeout = 5 * time.Minute
+
+// ChartTest 描述 Chart 中的一个测试 hook 及其配置
+type ChartTest struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+	// Weight 为 helm.sh/hook-weight，同一阶段的 hook 按权重升序执行
+	Weight int `json:"weight"`
+	// DeletePolicies 为 helm.sh/hook-delete-policy，未设置时 helm 使用 before-hook-creation
+	DeletePolicies []string `json:"deletePolicies"`
+	// RunsByDefault 表示不带 --filter 的 helm test 是否会运行该测试；helm 会运行所有测试 hook，
+	// 仅在使用 --filter name=... 时跳过其它测试
+	RunsByDefault bool   `json:"runsByDefault"`
+	Manifest      string `json:"manifest"`
+}
+
+// ListChartTests 渲染 Chart 并返回测试 hook 及其名称、权重与删除策略，按 helm test 的执行顺序排列
+func (s *HelmService) ListChartTests(name, version string) ([]ChartTest, error) {
+	if err := s.renderLimiter.acquire(context.Background()); err != nil {
+		return nil, err
+	}
+	defer s.renderLimiter.release()
+
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := s.renderRelease(chart, nil, chart.Metadata.Name, s.defaultNamespace, RenderOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	tests := []ChartTest{}
+	for _, hook := range rel.Hooks {
+		if !isTestHook(hook) {
+			continue
+		}
+
+		policies := make([]string, 0, len(hook.DeletePolicies))
+		for _, policy := range hook.DeletePolicies {
+			policies = append(policies, string(policy))
+		}
+		if len(policies) == 0 {
+			policies = append(policies, string(release.HookBeforeHookCreation))
+		}
+
+		tests = append(tests, ChartTest{
+			Name:           hook.Name,
+			Kind:           hook.Kind,
+			Path:           hook.Path,
+			Weight:         hook.Weight,
+			DeletePolicies: policies,
+			RunsByDefault:  true,
+			Manifest:       fmt.Sprintf("# Source: %s\n%s", hook.Path, hook.Manifest),
+		})
+	}
+
+	// helm 按权重升序执行 hook，权重相同时按名称排序
+	sort.SliceStable(tests, func(i, j int) bool {
+		if tests[i].Weight != tests[j].Weight {
+			return tests[i].Weight < tests[j].Weight
+		}
+		return tests[i].Name < tests[j].Name
+	})
+
+	return tests, nil
+}
+
+// isTestHook 判断 hook 是否为 helm test 使用的测试 hook
+func isTestHook(hook *release.Hook) bool {
+	for _, event := range hook.Events {
+		if event == release.HookTest {
+			return true
+		}
+	}
+	return false
+}
+
+// TestRun 描述单个测试 hook 的运行结果
+type TestRun struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+}
+
+// ReleaseTestResult 汇总 release 测试的运行结果
+type ReleaseTestResult struct {
+	Passed bool      `json:"passed"`
+	Tests  []TestRun `json:"tests"`
+	Logs   string    `json:"logs"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// RunReleaseTests 在集群中运行 release 的测试 hook，并返回测试 Pod 的日志
+func (s *HelmService) RunReleaseTests(releaseName, namespace string) (*ReleaseTestResult, error) {
+	if _, err := s.kubeClient(); err != nil {
+		return nil, err
+	}
+
+	actionConfig, err := s.newActionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	client := action.NewReleaseTesting(actionConfig)
+	client.Namespace = namespace
+	client.Timeout = releaseTestTimeout
+
+	result := &ReleaseTestResult{Tests: []TestRun{}}
+	rel, runErr := client.Run(releaseName)
+	if runErr != nil && errors.Is(runErr, driver.ErrReleaseNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrReleaseNotFound, releaseName)
+	}
+	if rel == nil {
+		return nil, fmt.Errorf("failed to run release tests: %w", runErr)
+	}
+
+	result.Passed = runErr == nil
+	if runErr != nil {
+		result.Error = runErr.Error()
+	}
+
+	for _, hook := range rel.Hooks {
+		if !isTestHook(hook) {
+			continue
+		}
+		result.Tests = append(result.Tests, TestRun{Name: hook.Name, Phase: string(hook.LastRun.Phase)})
+		if hook.LastRun.Phase != release.HookPhaseSucceeded {
+			result.Passed = false
+		}
+	}
+
+	var logs bytes.Buffer
+	if err := client.GetPodLogs(&logs, rel); err != nil {
+		fmt.Fprintf(&logs, "failed to fetch test pod logs: %v\n", err)
+	}
+	result.Logs = logs.String()
+
+	return result, nil
+}
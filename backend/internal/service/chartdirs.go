@@ -0,0 +1,54 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+// chartDirs 保存以别名注册的未打包 Chart 目录
+type chartDirs struct {
+	mu   sync.RWMutex
+	dirs map[string]string
+}
+
+// RegisterChartDir 将未打包的 Chart 目录注册为名为 alias 的 Chart，之后按该名称加载 Chart 时
+// 直接读取目录的当前内容（忽略请求的版本），便于开发时无需重复打包；已注册的别名会被覆盖
+func (s *HelmService) RegisterChartDir(alias, dir string) error {
+	if !profileNamePattern.MatchString(alias) {
+		return fmt.Errorf("%w: %s", ErrInvalidProfileName, alias)
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidChart, dir, err)
+	}
+	if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory", ErrInvalidChart, dir)
+	}
+	if err := checkDirSymlinks(abs); err != nil {
+		return err
+	}
+	if _, err := loader.LoadDir(abs); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidChart, err)
+	}
+
+	s.chartDirs.mu.Lock()
+	defer s.chartDirs.mu.Unlock()
+	if s.chartDirs.dirs == nil {
+		s.chartDirs.dirs = map[string]string{}
+	}
+	s.chartDirs.dirs[alias] = abs
+	return nil
+}
+
+// registeredChartDir 返回别名对应的 Chart 目录
+func (s *HelmService) registeredChartDir(name string) (string, bool) {
+	s.chartDirs.mu.RLock()
+	defer s.chartDirs.mu.RUnlock()
+	dir, ok := s.chartDirs.dirs[name]
+	return dir, ok
+}
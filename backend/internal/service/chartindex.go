@@ -0,0 +1,118 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+// CategoryAnnotation 是 Artifact Hub 用于 Chart 分类的注解
+const CategoryAnnotation = "artifacthub.io/category"
+
+// cachedMetadata 记录 Chart 包的元数据及读取时的文件状态
+type cachedMetadata struct {
+	modTime  time.Time
+	size     int64
+	metadata *chart.Metadata
+}
+
+// metadataCache 按文件路径缓存 Chart 包的元数据（含 annotations），文件修改时间或大小变化后失效
+type metadataCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedMetadata
+	hits    int64
+	misses  int64
+}
+
+// ChartListOptions 定义列出 Chart 时的过滤条件
+type ChartListOptions struct {
+	// IncludeDeprecated 为 false 时过滤掉已废弃的 Chart
+	IncludeDeprecated bool
+	// Category 非空时只返回 artifacthub.io/category 注解与之匹配（不区分大小写）的 Chart
+	Category string
+}
+
+// filtered 判断是否需要读取元数据来过滤
+func (o ChartListOptions) filtered() bool {
+	return !o.IncludeDeprecated || o.Category != ""
+}
+
+// matches 判断 Chart 元数据是否满足过滤条件
+func (o ChartListOptions) matches(metadata *chart.Metadata) bool {
+	if !o.IncludeDeprecated && metadata.Deprecated {
+		return false
+	}
+	if o.Category != "" && !strings.EqualFold(metadata.Annotations[CategoryAnnotation], o.Category) {
+		return false
+	}
+	return true
+}
+
+// includeArchive 判断包路径为 path 的 Chart 是否满足过滤条件；
+// 无法读取的包只在不按分类过滤时保留，与此前按废弃状态过滤的行为一致
+func (s *HelmService) includeArchive(path string, opts ChartListOptions) bool {
+	if !opts.filtered() {
+		return true
+	}
+	metadata, err := s.archiveMetadata(path)
+	if err != nil {
+		return opts.Category == ""
+	}
+	return opts.matches(metadata)
+}
+
+// archiveMetadata 返回 Chart 包的元数据，按修改时间与大小缓存
+func (s *HelmService) archiveMetadata(path string) (*chart.Metadata, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat chart file: %w", err)
+	}
+
+	s.metadata.mu.Lock()
+	cached, ok := s.metadata.entries[path]
+	hit := ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size()
+	if hit {
+		s.metadata.hits++
+	} else {
+		s.metadata.misses++
+	}
+	s.metadata.mu.Unlock()
+	if hit {
+		return cached.metadata, nil
+	}
+
+	c, err := loader.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart: %w", err)
+	}
+
+	s.metadata.mu.Lock()
+	if s.metadata.entries == nil {
+		s.metadata.entries = map[string]cachedMetadata{}
+	}
+	s.metadata.entries[path] = cachedMetadata{modTime: info.ModTime(), size: info.Size(), metadata: c.Metadata}
+	s.metadata.mu.Unlock()
+
+	return c.Metadata, nil
+}
+
+// MetadataCacheStats 返回 Chart 元数据缓存的统计信息
+func (s *HelmService) MetadataCacheStats() CacheStats {
+	s.metadata.mu.Lock()
+	defer s.metadata.mu.Unlock()
+	return CacheStats{Hits: s.metadata.hits, Misses: s.metadata.misses, Size: len(s.metadata.entries)}
+}
+
+// ClearMetadataCache 清空 Chart 元数据缓存，返回清除的条目数
+func (s *HelmService) ClearMetadataCache() int {
+	s.metadata.mu.Lock()
+	defer s.metadata.mu.Unlock()
+	cleared := len(s.metadata.entries)
+	s.metadata.entries = nil
+	return cleared
+}
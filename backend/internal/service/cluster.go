@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// clusterTimeout 是访问集群时单次请求的超时时间
+const clusterTimeout = 5 * time.Second
+
+// kubeClient 创建 Kubernetes 客户端，集群不可达时返回 ErrClusterUnavailable
+func (s *HelmService) kubeClient() (kubernetes.Interface, error) {
+	restConfig, err := s.restClientGetter().ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrClusterUnavailable, err)
+	}
+	restConfig.Timeout = clusterTimeout
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrClusterUnavailable, err)
+	}
+
+	// 通过获取服务端版本确认集群可达
+	if _, err := clientset.Discovery().ServerVersion(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrClusterUnavailable, err)
+	}
+
+	return clientset, nil
+}
+
+// ValuesFromConfigMap 从 ConfigMap 的指定 key 中读取 values
+func (s *HelmService) ValuesFromConfigMap(namespace, name, key string) (map[string]interface{}, error) {
+	client, err := s.kubeClient()
+	if err != nil {
+		return nil, err
+	}
+
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: configmap %s/%s", ErrValuesSourceNotFound, namespace, name)
+		}
+		return nil, fmt.Errorf("failed to get configmap %s/%s: %w", namespace, name, err)
+	}
+
+	data, ok := cm.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: key %q in configmap %s/%s", ErrValuesSourceNotFound, key, namespace, name)
+	}
+
+	return parseValues([]byte(data), fmt.Sprintf("configmap %s/%s", namespace, name))
+}
+
+// ValuesFromSecret 从 Secret 的指定 key 中读取 values
+func (s *HelmService) ValuesFromSecret(namespace, name, key string) (map[string]interface{}, error) {
+	client, err := s.kubeClient()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: secret %s/%s", ErrValuesSourceNotFound, namespace, name)
+		}
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", namespace, name, err)
+	}
+
+	// Secret.Data 在反序列化时已完成 base64 解码
+	data, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: key %q in secret %s/%s", ErrValuesSourceNotFound, key, namespace, name)
+	}
+
+	return parseValues(data, fmt.Sprintf("secret %s/%s", namespace, name))
+}
+
+// parseValues 将 YAML 内容解析为 values
+func parseValues(data []byte, source string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse values from %s: %w", source, err)
+	}
+	return values, nil
+}
+
+// ListNamespaces 列出集群中的命名空间，labelSelector 为空时返回全部
+func (s *HelmService) ListNamespaces(labelSelector string) ([]string, error) {
+	// 先在本地解析，避免非法的选择器以集群错误的形式返回
+	if _, err := labels.Parse(labelSelector); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSelector, err)
+	}
+
+	client, err := s.kubeClient()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := client.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		if apierrors.IsBadRequest(err) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSelector, err)
+		}
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	namespaces := make([]string, 0, len(list.Items))
+	for _, ns := range list.Items {
+		namespaces = append(namespaces, ns.Name)
+	}
+	sort.Strings(namespaces)
+
+	return namespaces, nil
+}
+
+// ClusterInfo 描述当前连接的集群
+type ClusterInfo struct {
+	Host          string `json:"host"`
+	ServerVersion string `json:"serverVersion"`
+}
+
+// ClusterInfo 检查集群连通性并返回集群地址与版本
+func (s *HelmService) ClusterInfo() (*ClusterInfo, error) {
+	restConfig, err := s.restClientGetter().ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrClusterUnavailable, err)
+	}
+
+	client, err := s.kubeClient()
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrClusterUnavailable, err)
+	}
+
+	return &ClusterInfo{Host: restConfig.Host, ServerVersion: version.GitVersion}, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// 渲染结果与集群中对象对比后的操作类型
+const (
+	DiffActionCreate   = "create"
+	DiffActionUpdate   = "update"
+	DiffActionNoChange = "no-change"
+)
+
+// ResourceDiff 描述单个渲染出的对象与集群中当前对象的差异
+type ResourceDiff struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	Action     string `json:"action"`
+	Diff       string `json:"diff,omitempty"`
+}
+
+// ClusterDiff 渲染 Chart 并将每个对象与集群中的当前对象对比。
+// 只比较渲染结果中出现的字段，服务端填充的默认值和 status 不计入差异
+func (s *HelmService) ClusterDiff(ctx context.Context, name, version string, values map[string]interface{}, releaseName, namespace string, opts RenderOptions) ([]ResourceDiff, error) {
+	if _, err := s.kubeClient(); err != nil {
+		return nil, err
+	}
+
+	// hook 不属于 release 持续管理的对象
+	opts.NoHooks = true
+	rendered, err := s.RenderChartContext(ctx, name, version, values, releaseName, namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	actionConfig, err := s.newActionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := actionConfig.KubeClient.Build(bytes.NewBufferString(rendered.Manifest), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build rendered resources: %w", err)
+	}
+
+	diffs := make([]ResourceDiff, 0, len(infos))
+	for _, info := range infos {
+		gvk := info.Mapping.GroupVersionKind
+		d := ResourceDiff{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Name:       info.Name,
+			Namespace:  info.Namespace,
+		}
+		id := fmt.Sprintf("%s/%s", gvk.Kind, info.Name)
+
+		desired, err := runtime.DefaultUnstructuredConverter.ToUnstructured(info.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", id, err)
+		}
+
+		var live map[string]interface{}
+		obj, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name)
+		switch {
+		case apierrors.IsNotFound(err):
+			d.Action = DiffActionCreate
+		case err != nil:
+			return nil, fmt.Errorf("failed to get %s: %w", id, err)
+		default:
+			if live, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj); err != nil {
+				return nil, fmt.Errorf("failed to convert live %s: %w", id, err)
+			}
+			live, _ = pruneToShape(live, desired).(map[string]interface{})
+		}
+
+		if d.Diff, err = objectDiff(id, live, desired); err != nil {
+			return nil, err
+		}
+		if d.Action == "" {
+			d.Action = DiffActionUpdate
+			if d.Diff == "" {
+				d.Action = DiffActionNoChange
+			}
+		}
+		diffs = append(diffs, d)
+	}
+
+	return diffs, nil
+}
+
+// objectDiff 以 YAML 形式对比集群中的对象与渲染出的对象，live 为 nil 表示对象不存在
+func objectDiff(id string, live, desired map[string]interface{}) (string, error) {
+	var from []byte
+	if live != nil {
+		data, err := yaml.Marshal(live)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode live %s: %w", id, err)
+		}
+		from = data
+	}
+	to, err := yaml.Marshal(desired)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode rendered %s: %w", id, err)
+	}
+	return unifiedDiff("live/"+id, "rendered/"+id, string(from), string(to))
+}
+
+// pruneToShape 只保留 live 中在 desired 里出现的字段，长度相同的列表按元素递归处理
+func pruneToShape(live, desired interface{}) interface{} {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		pruned := make(map[string]interface{}, len(d))
+		for k, v := range d {
+			if lv, ok := l[k]; ok {
+				pruned[k] = pruneToShape(lv, v)
+			}
+		}
+		return pruned
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			return live
+		}
+		pruned := make([]interface{}, len(l))
+		for i := range l {
+			pruned[i] = pruneToShape(l[i], d[i])
+		}
+		return pruned
+	}
+	return live
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestListNamespacesInvalidSelector(t *testing.T) {
+	// 非法的选择器在访问集群之前被拒绝，测试不需要可用的集群
+	s := NewHelmService()
+
+	tests := []struct {
+		name     string
+		selector string
+	}{
+		{"unbalanced set", "env in (prod"},
+		{"invalid value", "env=has space"},
+		{"invalid key", "-env=prod"},
+		{"invalid operator", "env<>prod"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.ListNamespaces(tt.selector); !errors.Is(err, ErrInvalidSelector) {
+				t.Errorf("ListNamespaces(%q) err = %v, want %v", tt.selector, err, ErrInvalidSelector)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// debugCollector 收集 helm action 输出的调试日志
+type debugCollector struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+// logf 实现 action.DebugLog
+func (d *debugCollector) logf(format string, v ...interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lines = append(d.lines, fmt.Sprintf(format, v...))
+}
+
+// RenderDebugError 是 debug 模式下的渲染错误，相当于 helm --debug 输出的信息
+type RenderDebugError struct {
+	// Err 为 helm 返回的原始错误
+	Err error `json:"-"`
+	// Detail 为包含调用栈的详细错误信息
+	Detail string `json:"detail"`
+	// Log 为渲染过程中 helm 输出的调试日志
+	Log []string `json:"log"`
+	// PartialManifest 为渲染失败前已生成的 manifest（如 YAML 解析失败时的模板输出）
+	PartialManifest string `json:"partialManifest,omitempty"`
+}
+
+// newRenderDebugError 根据 helm 的错误、失败的 release 与调试日志构建 RenderDebugError
+func newRenderDebugError(err error, rel *release.Release, log *debugCollector) *RenderDebugError {
+	debugErr := &RenderDebugError{
+		Err:    err,
+		Detail: fmt.Sprintf("%+v", err),
+		Log:    []string{},
+	}
+	if rel != nil {
+		debugErr.PartialManifest = rel.Manifest
+	}
+	if log != nil {
+		log.mu.Lock()
+		debugErr.Log = append(debugErr.Log, log.lines...)
+		log.mu.Unlock()
+	}
+	return debugErr
+}
+
+// Error 实现 error，与非 debug 模式的错误信息一致
+func (e *RenderDebugError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrRenderFailed, e.Err)
+}
+
+// Is 使 errors.Is(err, ErrRenderFailed) 成立
+func (e *RenderDebugError) Is(target error) bool {
+	return target == ErrRenderFailed
+}
+
+// Unwrap 返回 helm 的原始错误
+func (e *RenderDebugError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// releaseNameData 是 release 名称模板可用的字段
+type releaseNameData struct {
+	ChartName    string
+	ChartVersion string
+	Namespace    string
+}
+
+// loadReleaseNameTemplate 解析 HELM_UI_RELEASE_NAME_TEMPLATE 配置的 release 名称模板
+func loadReleaseNameTemplate() *template.Template {
+	text := os.Getenv("HELM_UI_RELEASE_NAME_TEMPLATE")
+	if text == "" {
+		return nil
+	}
+
+	tpl, err := template.New("release-name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		log.Printf("ignoring invalid release name template: %v", err)
+		return nil
+	}
+	return tpl
+}
+
+// DefaultNamespace 返回未指定命名空间时使用的默认命名空间
+func (s *HelmService) DefaultNamespace() string {
+	return s.defaultNamespace
+}
+
+// GenerateReleaseName 按配置的模板生成 release 名称，未配置模板时返回空字符串
+func (s *HelmService) GenerateReleaseName(chartName, chartVersion, namespace string) (string, error) {
+	if s.releaseNameTemplate == nil {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	data := releaseNameData{ChartName: chartName, ChartVersion: chartVersion, Namespace: namespace}
+	if err := s.releaseNameTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to generate release name: %w", err)
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// DependencyStatus 描述 Chart.yaml 中声明的一个依赖是否已随 Chart 一起打包
+type DependencyStatus struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Repository string `json:"repository,omitempty"`
+	Alias      string `json:"alias,omitempty"`
+	Condition  string `json:"condition,omitempty"`
+	// Required 为 true 表示依赖没有 condition 或 tags，总会被渲染
+	Required bool `json:"required"`
+	Vendored bool `json:"vendored"`
+}
+
+// DependenciesStatus 汇总 Chart 依赖的打包情况
+type DependenciesStatus struct {
+	Dependencies []DependencyStatus `json:"dependencies"`
+	// Missing 为未打包的必需依赖
+	Missing []string `json:"missing"`
+}
+
+// GetDependenciesStatus 检查 Chart 声明的依赖是否都已打包在 charts/ 目录中
+func (s *HelmService) GetDependenciesStatus(name, version string) (*DependenciesStatus, error) {
+	c, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+	return dependenciesStatus(c), nil
+}
+
+// dependenciesStatus 对比 Chart.yaml 中声明的依赖与实际打包的子 Chart
+func dependenciesStatus(c *chart.Chart) *DependenciesStatus {
+	status := &DependenciesStatus{
+		Dependencies: []DependencyStatus{},
+		Missing:      []string{},
+	}
+
+	for _, dep := range c.Metadata.Dependencies {
+		if dep == nil {
+			continue
+		}
+
+		ds := DependencyStatus{
+			Name:       dep.Name,
+			Version:    dep.Version,
+			Repository: dep.Repository,
+			Alias:      dep.Alias,
+			Condition:  dep.Condition,
+			Required:   strings.TrimSpace(dep.Condition) == "" && len(dep.Tags) == 0,
+			Vendored:   isVendored(c, dep),
+		}
+		status.Dependencies = append(status.Dependencies, ds)
+
+		if ds.Required && !ds.Vendored {
+			status.Missing = append(status.Missing, fmt.Sprintf("%s (%s)", dep.Name, dep.Version))
+		}
+	}
+
+	return status
+}
+
+// isVendored 判断依赖是否存在名称与版本范围都匹配的子 Chart
+func isVendored(c *chart.Chart, dep *chart.Dependency) bool {
+	for _, sub := range c.Dependencies() {
+		if sub.Name() == dep.Name && (dep.Version == "" || chartutil.IsCompatibleRange(dep.Version, sub.Metadata.Version)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDependencies 在渲染前确认必需的依赖均已打包，缺失时返回 ErrMissingDependencies
+func checkDependencies(c *chart.Chart) error {
+	status := dependenciesStatus(c)
+	if len(status.Missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s; run 'helm dependency update' and re-upload the chart",
+		ErrMissingDependencies, strings.Join(status.Missing, ", "))
+}
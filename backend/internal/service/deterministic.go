@@ -0,0 +1,189 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"text/template/parse"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// 确定性渲染时使用的固定值
+const (
+	// FrozenTime 是 now 返回的固定时间
+	FrozenTime = "1970-01-01T00:00:00Z"
+	// FrozenUUID 是 uuidv4 返回的固定值
+	FrozenUUID = "00000000-0000-4000-8000-000000000000"
+)
+
+// frozenValueFuncs 是无参数、在确定性渲染时替换为固定值的函数
+var frozenValueFuncs = map[string]bool{"now": true, "uuidv4": true}
+
+// frozenRandFuncs 是按长度生成随机字符串的函数，确定性渲染时改为用固定字符重复相同长度
+var frozenRandFuncs = map[string]string{
+	"randAlphaNum": "a",
+	"randAlpha":    "a",
+	"randAscii":    "a",
+	"randNumeric":  "0",
+}
+
+// FrozenFunctions 返回确定性渲染时被冻结的模板函数：
+// now 固定为 FrozenTime，uuidv4 固定为 FrozenUUID，randAlphaNum、randAlpha、randAscii 与 randNumeric
+// 返回由 a（randNumeric 为 0）组成的指定长度字符串，randInt 返回下限。
+// genPrivateKey、genCA 等证书函数与 randBytes、shuffle 不会被冻结
+func FrozenFunctions() []string {
+	names := []string{"randInt"}
+	for name := range frozenValueFuncs {
+		names = append(names, name)
+	}
+	for name := range frozenRandFuncs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// freezeChartTemplates 改写 Chart 及其子 Chart 的模板，将时间与随机函数替换为固定值，使重复渲染的结果一致。
+// 无法解析的模板保持原样，由渲染时报告错误
+func freezeChartTemplates(c *chart.Chart) {
+	for _, tpl := range c.Templates {
+		if data, ok := freezeTemplate(tpl.Name, string(tpl.Data)); ok {
+			tpl.Data = []byte(data)
+		}
+	}
+	for _, dep := range c.Dependencies() {
+		freezeChartTemplates(dep)
+	}
+}
+
+// freezeTemplate 改写单个模板的源码，模板未使用被冻结的函数或无法解析时返回 false
+func freezeTemplate(name, text string) (string, bool) {
+	used := false
+	for _, fn := range FrozenFunctions() {
+		if strings.Contains(text, fn) {
+			used = true
+			break
+		}
+	}
+	if !used {
+		return "", false
+	}
+
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck
+	trees := map[string]*parse.Tree{}
+	if _, err := tree.Parse(text, "", "", trees); err != nil {
+		return "", false
+	}
+
+	// 重新生成源码时，define 的命名模板按名称排序追加在主模板之后
+	var b strings.Builder
+	if main, ok := trees[name]; ok {
+		freezeNode(main.Root)
+		b.WriteString(main.Root.String())
+	}
+	names := make([]string, 0, len(trees))
+	for n := range trees {
+		if n != name {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		freezeNode(trees[n].Root)
+		b.WriteString("{{define " + strconv.Quote(n) + "}}" + trees[n].Root.String() + "{{end}}")
+	}
+	return b.String(), true
+}
+
+// freezeNode 遍历模板语法树，改写其中的管道
+func freezeNode(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			freezeNode(child)
+		}
+	case *parse.ActionNode:
+		freezePipe(n.Pipe)
+	case *parse.IfNode:
+		freezeBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		freezeBranch(&n.BranchNode)
+	case *parse.WithNode:
+		freezeBranch(&n.BranchNode)
+	case *parse.TemplateNode:
+		freezePipe(n.Pipe)
+	}
+}
+
+// freezeBranch 改写 if/range/with 的条件与分支
+func freezeBranch(n *parse.BranchNode) {
+	freezePipe(n.Pipe)
+	freezeNode(n.List)
+	freezeNode(n.ElseList)
+}
+
+// freezePipe 改写管道中的命令。被冻结的随机函数以管道形式调用时（如 8 | randAlphaNum），
+// 先将前面的命令折叠为括号参数，使替换后的函数能按参数顺序调用
+func freezePipe(pipe *parse.PipeNode) {
+	if pipe == nil {
+		return
+	}
+
+	var cmds []*parse.CommandNode
+	for _, cmd := range pipe.Cmds {
+		for i, arg := range cmd.Args {
+			cmd.Args[i] = freezeArg(arg)
+		}
+
+		ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+		if !ok || (ident.Ident != "randInt" && frozenRandFuncs[ident.Ident] == "") {
+			cmds = append(cmds, cmd)
+			continue
+		}
+
+		if len(cmds) > 0 {
+			cmd.Args = append(cmd.Args, &parse.PipeNode{NodeType: parse.NodePipe, Cmds: cmds})
+			cmds = nil
+		}
+		if ident.Ident == "randInt" {
+			// randInt min max 的结果不小于 min，min 函数恰好返回两者中较小的下限
+			cmd.Args[0] = parse.NewIdentifier("min")
+		} else {
+			cmd.Args = append([]parse.Node{parse.NewIdentifier("repeat")}, append(cmd.Args[1:], templateString(frozenRandFuncs[ident.Ident]))...)
+		}
+		cmds = append(cmds, cmd)
+	}
+	pipe.Cmds = cmds
+}
+
+// freezeArg 将 now 与 uuidv4 替换为固定值，并递归改写括号中的管道
+func freezeArg(arg parse.Node) parse.Node {
+	switch n := arg.(type) {
+	case *parse.IdentifierNode:
+		switch n.Ident {
+		case "now":
+			// 以带时区的格式解析固定的 UTC 时间
+			return &parse.PipeNode{NodeType: parse.NodePipe, Cmds: []*parse.CommandNode{{
+				NodeType: parse.NodeCommand,
+				Args:     []parse.Node{parse.NewIdentifier("toDate"), templateString("2006-01-02T15:04:05Z07:00"), templateString(FrozenTime)},
+			}}}
+		case "uuidv4":
+			return templateString(FrozenUUID)
+		}
+	case *parse.PipeNode:
+		freezePipe(n)
+	case *parse.ChainNode:
+		n.Node = freezeArg(n.Node)
+	}
+	return arg
+}
+
+// templateString 创建字符串常量节点
+func templateString(s string) *parse.StringNode {
+	return &parse.StringNode{NodeType: parse.NodeString, Quoted: strconv.Quote(s), Text: s}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestFreezeTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		changed bool
+		want    string
+	}{
+		{"no frozen function", `name: {{ .Release.Name }}`, false, ""},
+		{"now", `t: {{ now }}`, true, `toDate "2006-01-02T15:04:05Z07:00" "1970-01-01T00:00:00Z"`},
+		{"uuidv4", `id: {{ uuidv4 }}`, true, `"00000000-0000-4000-8000-000000000000"`},
+		{"randAlphaNum call", `p: {{ randAlphaNum 8 }}`, true, `repeat 8 "a"`},
+		{"randNumeric pipeline", `p: {{ 6 | randNumeric }}`, true, `repeat (6) "0"`},
+		{"randInt", `n: {{ randInt 3 9 }}`, true, `min 3 9`},
+		{"inside define", `{{ define "x" }}{{ randAlpha 4 }}{{ end }}`, true, `{{define "x"}}{{repeat 4 "a"}}{{end}}`},
+		{"unparsable", `{{ randAlpha 4 `, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := freezeTemplate("templates/x.yaml", tt.text)
+			if changed != tt.changed {
+				t.Fatalf("changed = %v, want %v", changed, tt.changed)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("freezeTemplate() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderChartDeterministic(t *testing.T) {
+	secret := []byte("apiVersion: v1\nkind: Secret\nmetadata:\n  name: s\nstringData:\n  password: {{ randAlphaNum 16 }}\n  id: {{ uuidv4 }}\n")
+	c := &chart.Chart{
+		Metadata:  &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "det", Version: "0.1.0"},
+		Templates: []*chart.File{{Name: "templates/secret.yaml", Data: secret}},
+	}
+	// broken 中的 fail 模板使整体渲染失败，从而走到逐个渲染的回退路径
+	broken := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "broken", Version: "0.1.0"},
+		Templates: []*chart.File{
+			{Name: "templates/secret.yaml", Data: secret},
+			{Name: "templates/fail.yaml", Data: []byte(`{{ fail "boom" }}`)},
+		},
+	}
+	s := newRenderTestService(t, c, broken)
+
+	tests := []struct {
+		name       string
+		chart      string
+		bestEffort bool
+		wantErrors int
+	}{
+		{"render", "det", false, 0},
+		{"best effort", "det", true, 0},
+		{"best effort fallback", "broken", true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			render := func() string {
+				t.Helper()
+				opts := RenderOptions{Deterministic: true}
+				var result *RenderResult
+				var err error
+				if tt.bestEffort {
+					result, err = s.RenderChartBestEffort(context.Background(), tt.chart, "0.1.0", nil, "r", "default", opts)
+				} else {
+					result, err = s.RenderChart(tt.chart, "0.1.0", nil, "r", "default", opts)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(result.Errors) != tt.wantErrors {
+					t.Fatalf("got %d template errors, want %d: %v", len(result.Errors), tt.wantErrors, result.Errors)
+				}
+				return result.Manifest
+			}
+
+			first, second := render(), render()
+			if first != second {
+				t.Errorf("deterministic renders differ:\n%s\n---\n%s", first, second)
+			}
+			if !strings.Contains(first, "password: "+strings.Repeat("a", 16)) || !strings.Contains(first, FrozenUUID) {
+				t.Errorf("manifest does not use frozen values:\n%s", first)
+			}
+		})
+	}
+}
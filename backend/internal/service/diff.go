@@ -0,0 +1,33 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// diffContextLines 是统一 diff 中保留的上下文行数
+const diffContextLines = 3
+
+// unifiedDiff 生成两段文本之间的统一格式 diff，内容相同时返回空字符串
+func unifiedDiff(fromName, toName, from, to string) (string, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(from),
+		B:        diffLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  diffContextLines,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate diff: %w", err)
+	}
+	return diff, nil
+}
+
+// diffLines 按行拆分文本，空文本不产生任何行
+func diffLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return difflib.SplitLines(s)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// cachedDigest 记录 Chart 包的摘要及计算时的文件状态
+type cachedDigest struct {
+	modTime time.Time
+	size    int64
+	digest  string
+}
+
+// digestCache 按文件路径缓存 Chart 包摘要，文件修改时间或大小变化后失效
+type digestCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedDigest
+	hits    int64
+	misses  int64
+}
+
+// CacheStats 描述缓存的命中情况与当前条目数
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	Size   int   `json:"size"`
+}
+
+// DigestCacheStats 返回 Chart 包摘要缓存的统计信息
+func (s *HelmService) DigestCacheStats() CacheStats {
+	s.digests.mu.Lock()
+	defer s.digests.mu.Unlock()
+	return CacheStats{Hits: s.digests.hits, Misses: s.digests.misses, Size: len(s.digests.entries)}
+}
+
+// ClearDigestCache 清空 Chart 包摘要缓存，返回清除的条目数
+func (s *HelmService) ClearDigestCache() int {
+	s.digests.mu.Lock()
+	defer s.digests.mu.Unlock()
+	cleared := len(s.digests.entries)
+	s.digests.entries = nil
+	return cleared
+}
+
+// ChartDigest 返回 Chart 包的 SHA256 摘要，格式为 sha256:<hex>
+func (s *HelmService) ChartDigest(name, version string) (string, error) {
+	path, err := s.ChartArchivePath(name, version)
+	if err != nil {
+		return "", err
+	}
+	return s.fileDigest(path)
+}
+
+// fileDigest 以流式方式计算文件的 SHA256 摘要，并按修改时间缓存结果
+func (s *HelmService) fileDigest(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat chart file: %w", err)
+	}
+
+	s.digests.mu.Lock()
+	cached, ok := s.digests.entries[path]
+	hit := ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size()
+	if hit {
+		s.digests.hits++
+	} else {
+		s.digests.misses++
+	}
+	s.digests.mu.Unlock()
+	if hit {
+		return cached.digest, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open chart file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash chart file: %w", err)
+	}
+	digest := "sha256:" + hex.EncodeToString(h.Sum(nil))
+
+	s.digests.mu.Lock()
+	if s.digests.entries == nil {
+		s.digests.entries = map[string]cachedDigest{}
+	}
+	s.digests.entries[path] = cachedDigest{modTime: info.ModTime(), size: info.Size(), digest: digest}
+	s.digests.mu.Unlock()
+
+	return digest, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envRefPattern 匹配 values 字符串中的 ${env:NAME} 引用
+var envRefPattern = regexp.MustCompile(`\$\{env:([^}]*)\}`)
+
+// EnvSubstTransformer 将字符串 values 中的 ${env:NAME} 替换为服务端环境变量的值，
+// 只允许引用白名单中的变量，引用未允许或未设置的变量时返回 ErrInvalidValues
+type EnvSubstTransformer struct {
+	Allowed map[string]bool
+}
+
+// NewEnvSubstTransformer 从 "NAME,NAME2" 形式的白名单创建转换器
+func NewEnvSubstTransformer(spec string) *EnvSubstTransformer {
+	allowed := map[string]bool{}
+	for _, name := range strings.Split(spec, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			allowed[name] = true
+		}
+	}
+	return &EnvSubstTransformer{Allowed: allowed}
+}
+
+// Transform 实现 ValueTransformer，递归替换 map 与数组中的字符串
+func (t *EnvSubstTransformer) Transform(_ string, values map[string]interface{}) (map[string]interface{}, error) {
+	result, err := t.substitute(values, "")
+	if err != nil {
+		return nil, err
+	}
+	return result.(map[string]interface{}), nil
+}
+
+// substitute 替换 path 处的值中的环境变量引用
+func (t *EnvSubstTransformer) substitute(v interface{}, path string) (interface{}, error) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, child := range value {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			substituted, err := t.substitute(child, childPath)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = substituted
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			substituted, err := t.substitute(item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = substituted
+		}
+		return result, nil
+	case string:
+		return t.expand(value, path)
+	default:
+		return v, nil
+	}
+}
+
+// expand 替换单个字符串中的所有环境变量引用
+func (t *EnvSubstTransformer) expand(s, path string) (string, error) {
+	var expandErr error
+	result := envRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if expandErr != nil {
+			return ref
+		}
+		name := envRefPattern.FindStringSubmatch(ref)[1]
+		if !t.Allowed[name] {
+			expandErr = fmt.Errorf("%w: %s: environment variable %q is not allowed", ErrInvalidValues, path, name)
+			return ref
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			expandErr = fmt.Errorf("%w: %s: environment variable %q is not set", ErrInvalidValues, path, name)
+			return ref
+		}
+		return value
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return result, nil
+}
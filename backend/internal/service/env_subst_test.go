@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEnvSubstTransformer(t *testing.T) {
+	t.Setenv("HELM_UI_TEST_HOST", "db.internal")
+	t.Setenv("HELM_UI_TEST_PORT", "5432")
+	t.Setenv("HELM_UI_TEST_SECRET", "s3cret")
+	transformer := NewEnvSubstTransformer(" HELM_UI_TEST_HOST, HELM_UI_TEST_PORT,,HELM_UI_TEST_UNSET ")
+
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:   "no references",
+			values: map[string]interface{}{"replicas": 2, "name": "app", "enabled": true},
+			want:   map[string]interface{}{"replicas": 2, "name": "app", "enabled": true},
+		},
+		{
+			name:   "nested maps and lists",
+			values: map[string]interface{}{"db": map[string]interface{}{"url": "postgres://${env:HELM_UI_TEST_HOST}:${env:HELM_UI_TEST_PORT}/app"}, "hosts": []interface{}{"${env:HELM_UI_TEST_HOST}", 1}},
+			want:   map[string]interface{}{"db": map[string]interface{}{"url": "postgres://db.internal:5432/app"}, "hosts": []interface{}{"db.internal", 1}},
+		},
+		{
+			name:   "other placeholders untouched",
+			values: map[string]interface{}{"a": "${HOME}", "b": "$env:HELM_UI_TEST_HOST"},
+			want:   map[string]interface{}{"a": "${HOME}", "b": "$env:HELM_UI_TEST_HOST"},
+		},
+		{
+			name:    "not allowed",
+			values:  map[string]interface{}{"password": "${env:HELM_UI_TEST_SECRET}"},
+			wantErr: true,
+		},
+		{
+			name:    "allowed but unset",
+			values:  map[string]interface{}{"x": []interface{}{"${env:HELM_UI_TEST_UNSET}"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty name",
+			values:  map[string]interface{}{"x": "${env:}"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := transformer.Transform("app", tt.values)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidValues) {
+					t.Fatalf("Transform() error = %v, want %v", err, ErrInvalidValues)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Transform() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import "errors"
+
+var (
+	// ErrClusterUnavailable 表示无法连接到 Kubernetes 集群
+	ErrClusterUnavailable = errors.New("kubernetes cluster is unavailable")
+	// ErrValuesSourceNotFound 表示引用的 values 来源（ConfigMap/Secret 或其中的 key）不存在
+	ErrValuesSourceNotFound = errors.New("values source not found")
+	// ErrIconNotFound 表示 Chart 未设置图标
+	ErrIconNotFound = errors.New("chart has no icon")
+	// ErrReleaseNotFound 表示指定的 release 不存在
+	ErrReleaseNotFound = errors.New("release not found")
+	// ErrRenderQueueFull 表示排队等待渲染的请求已达上限
+	ErrRenderQueueFull = errors.New("too many concurrent render requests")
+	// ErrChartNotFound 表示指定的 Chart 版本不存在或无法加载
+	ErrChartNotFound = errors.New("chart not found")
+	// ErrRenderTimeout 表示 Chart 渲染超过了允许的时间
+	ErrRenderTimeout = errors.New("render timed out")
+	// ErrRenderFailed 表示 Chart 模板渲染失败
+	ErrRenderFailed = errors.New("failed to render chart")
+	// ErrInvalidChart 表示上传的内容不是合法的 Chart 包
+	ErrInvalidChart = errors.New("invalid chart archive")
+	// ErrProfileNotFound 表示引用的 values profile 不存在
+	ErrProfileNotFound = errors.New("values profile not found")
+	// ErrInvalidProfileName 表示 profile 或 Chart 名称包含非法字符
+	ErrInvalidProfileName = errors.New("invalid profile name")
+	// ErrInvalidPolicy 表示提供的 Rego 策略无法解析或编译
+	ErrInvalidPolicy = errors.New("invalid policy")
+	// ErrInvalidKubeconfig 表示上传的 kubeconfig 无法解析
+	ErrInvalidKubeconfig = errors.New("invalid kubeconfig")
+	// ErrSessionNotFound 表示集群会话不存在或已过期
+	ErrSessionNotFound = errors.New("cluster session not found or expired")
+	// ErrTooManySessions 表示集群会话数量已达上限
+	ErrTooManySessions = errors.New("too many cluster sessions")
+	// ErrTemplateNotFound 表示 Chart 中不存在指定的模板文件
+	ErrTemplateNotFound = errors.New("template not found")
+	// ErrTemplateEmpty 表示模板存在但在本次渲染中没有产生任何输出
+	ErrTemplateEmpty = errors.New("template produced no output")
+	// ErrMissingDependencies 表示 Chart 缺少必需的子 Chart 依赖
+	ErrMissingDependencies = errors.New("missing chart dependencies")
+	// ErrRepoNotFound 表示指定的 Helm 仓库未配置
+	ErrRepoNotFound = errors.New("repository not found")
+	// ErrRepoExists 表示同名的 Helm 仓库已配置
+	ErrRepoExists = errors.New("repository already exists")
+	// ErrRegistryUnauthorized 表示 OCI 仓库拒绝了提供的凭据或匿名访问
+	ErrRegistryUnauthorized = errors.New("registry authentication failed")
+	// ErrInvalidRepo 表示仓库名称或地址非法，或无法获取其索引
+	ErrInvalidRepo = errors.New("invalid repository")
+	// ErrUploadNotFound 表示分片上传不存在或已过期
+	ErrUploadNotFound = errors.New("upload not found")
+	// ErrUploadOffsetMismatch 表示分片的起始偏移与已接收的数据不连续
+	ErrUploadOffsetMismatch = errors.New("upload offset mismatch")
+	// ErrUploadTooLarge 表示分片上传的总大小超过上限
+	ErrUploadTooLarge = errors.New("upload too large")
+	// ErrInvalidValues 表示上传的 values 文件不是合法的 YAML
+	ErrInvalidValues = errors.New("invalid values file")
+	// ErrInvalidManifest 表示提交的 manifest 不是合法的 YAML
+	ErrInvalidManifest = errors.New("invalid manifest")
+	// ErrInvalidSelector 表示标签选择器无法解析
+	ErrInvalidSelector = errors.New("invalid label selector")
+)
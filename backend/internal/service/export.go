@@ -0,0 +1,208 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/yaml"
+)
+
+// exportIndexFile 是导出归档中仓库索引的文件名
+const exportIndexFile = "index.yaml"
+
+// storedArchivePaths 返回所有已存储 Chart 包的路径
+func (s *HelmService) storedArchivePaths() ([]string, error) {
+	if s.cas != nil {
+		entries, err := s.cas.entries()
+		if err != nil {
+			return nil, err
+		}
+		paths := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			paths = append(paths, s.cas.blobPath(entry.digest))
+		}
+		return paths, nil
+	}
+
+	files, err := os.ReadDir(s.chartsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read charts directory: %w", err)
+	}
+	var paths []string
+	for _, file := range files {
+		if !file.IsDir() && isChartArchive(file.Name()) {
+			paths = append(paths, filepath.Join(s.chartsDir, file.Name()))
+		}
+	}
+	return paths, nil
+}
+
+// ExportCharts 将所有已存储的 Chart 包以及生成的 index.yaml 以 tar.gz 格式流式写入 w，
+// 包按 <name>-<version>.tgz 命名，无法读取的包会被跳过
+func (s *HelmService) ExportCharts(w io.Writer) error {
+	paths, err := s.storedArchivePaths()
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	index := repo.NewIndexFile()
+	now := time.Now()
+	for _, p := range paths {
+		metadata, err := s.archiveMetadata(p)
+		if err != nil {
+			continue
+		}
+		digest, err := s.fileDigest(p)
+		if err != nil {
+			return err
+		}
+
+		filename := fmt.Sprintf("%s-%s.tgz", metadata.Name, metadata.Version)
+		if index.Has(metadata.Name, metadata.Version) {
+			continue
+		}
+		if err := index.MustAdd(metadata, filename, "", strings.TrimPrefix(digest, "sha256:")); err != nil {
+			continue
+		}
+		if err := writeTarFile(tw, filename, p, now); err != nil {
+			return err
+		}
+	}
+
+	index.SortEntries()
+	data, err := yaml.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+	header := &tar.Header{Name: exportIndexFile, Mode: 0644, Size: int64(len(data)), ModTime: now}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write export archive: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write export archive: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write export archive: %w", err)
+	}
+	return gz.Close()
+}
+
+// writeTarFile 以 name 为文件名将 src 写入 tar
+func writeTarFile(tw *tar.Writer, name, src string, modTime time.Time) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", name, err)
+	}
+
+	header := &tar.Header{Name: name, Mode: 0644, Size: info.Size(), ModTime: modTime}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write export archive: %w", err)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("failed to write export archive: %w", err)
+	}
+	return nil
+}
+
+// ImportOptions 定义导入归档的参数
+type ImportOptions struct {
+	// MaxChartBytes 为单个 Chart 包的大小上限
+	MaxChartBytes int64
+	// Overwrite 为 true 时覆盖已存在的同名同版本 Chart，否则跳过
+	Overwrite bool
+}
+
+// ImportResult 汇总导入归档的结果
+type ImportResult struct {
+	// Imported 与 Skipped 中的 Chart 格式为 <name>-<version>
+	Imported []string `json:"imported"`
+	Skipped  []string `json:"skipped"`
+	// Failed 为无法导入的文件及原因
+	Failed map[string]string `json:"failed"`
+}
+
+// ImportCharts 导入 ExportCharts 生成的归档：逐个校验并保存其中的 Chart 包，index.yaml 会被忽略。
+// 包始终按 <name>-<version>.tgz 保存；路径非法、超过大小上限或无法加载的包记录在 Failed 中，不影响其它包
+func (s *HelmService) ImportCharts(r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidChart, err)
+	}
+	defer gz.Close()
+
+	result := &ImportResult{Imported: []string{}, Skipped: []string{}, Failed: map[string]string{}}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("%w: %v", ErrInvalidChart, err)
+		}
+		if header.Typeflag != tar.TypeReg || !isChartArchive(header.Name) {
+			continue
+		}
+
+		// 拒绝绝对路径与包含 .. 的路径
+		if path.IsAbs(header.Name) || strings.Contains(header.Name, `\`) || slices.Contains(strings.Split(header.Name, "/"), "..") {
+			result.Failed[header.Name] = "invalid path"
+			continue
+		}
+		if header.Size > opts.MaxChartBytes {
+			result.Failed[header.Name] = "chart too large"
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, opts.MaxChartBytes))
+		if err != nil {
+			return result, fmt.Errorf("%w: %v", ErrInvalidChart, err)
+		}
+
+		if err := CheckArchiveEntries(bytes.NewReader(data)); err != nil {
+			result.Failed[header.Name] = err.Error()
+			continue
+		}
+		chart, err := loader.LoadArchive(bytes.NewReader(data))
+		if err != nil {
+			result.Failed[header.Name] = fmt.Sprintf("%v: %v", ErrInvalidChart, err)
+			continue
+		}
+		id := chart.Metadata.Name + "-" + chart.Metadata.Version
+		if !opts.Overwrite {
+			if _, err := s.ChartArchivePath(chart.Metadata.Name, chart.Metadata.Version); err == nil {
+				result.Skipped = append(result.Skipped, id)
+				continue
+			}
+		}
+
+		if err := s.UploadChart(bytes.NewReader(data), id+".tgz"); err != nil {
+			result.Failed[header.Name] = err.Error()
+			continue
+		}
+		result.Imported = append(result.Imported, id)
+	}
+
+	return result, nil
+}
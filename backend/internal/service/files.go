@@ -0,0 +1,110 @@
+package service
+
+import (
+	"sort"
+	"strings"
+)
+
+// ChartFileOptions 定义 Chart 文件列表的过滤与分页参数
+type ChartFileOptions struct {
+	// Prefix 仅保留以此开头的文件，例如 templates/
+	Prefix string
+	// Limit 为 0 时不分页
+	Limit  int
+	Offset int
+}
+
+// ChartFileList 是分页后的文件列表，Total 为过滤后的总数
+type ChartFileList struct {
+	Files []string `json:"files"`
+	Total int      `json:"total"`
+}
+
+// FileNode 描述 Chart 文件树中的文件或目录
+type FileNode struct {
+	Name     string      `json:"name"`
+	Path     string      `json:"path"`
+	Dir      bool        `json:"dir"`
+	Children []*FileNode `json:"children,omitempty"`
+}
+
+// filterChartFiles 按前缀过滤并排序文件列表
+func filterChartFiles(files []string, prefix string) []string {
+	filtered := []string{}
+	for _, f := range files {
+		if strings.HasPrefix(f, prefix) {
+			filtered = append(filtered, f)
+		}
+	}
+	sort.Strings(filtered)
+	return filtered
+}
+
+// ListChartFilesPage 按前缀过滤并分页列出 Chart 包含的文件
+func (s *HelmService) ListChartFilesPage(name, version string, opts ChartFileOptions) (*ChartFileList, error) {
+	files, err := s.ListChartFiles(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	files = filterChartFiles(files, opts.Prefix)
+	list := &ChartFileList{Total: len(files)}
+
+	start := min(opts.Offset, len(files))
+	end := len(files)
+	if opts.Limit > 0 {
+		end = min(start+opts.Limit, len(files))
+	}
+	list.Files = files[start:end]
+	return list, nil
+}
+
+// ChartFileTree 将 Chart 包含的文件按目录组织为树，prefix 非空时只包含匹配的文件
+func (s *HelmService) ChartFileTree(name, version, prefix string) (*FileNode, error) {
+	files, err := s.ListChartFiles(name, version)
+	if err != nil {
+		return nil, err
+	}
+	return buildFileTree(filterChartFiles(files, prefix)), nil
+}
+
+// buildFileTree 根据有序的文件路径构建目录树，目录排在文件之前
+func buildFileTree(files []string) *FileNode {
+	root := &FileNode{Dir: true}
+	dirs := map[string]*FileNode{"": root}
+
+	for _, file := range files {
+		parent := root
+		parts := strings.Split(file, "/")
+		for i, part := range parts[:len(parts)-1] {
+			path := strings.Join(parts[:i+1], "/")
+			dir, ok := dirs[path]
+			if !ok {
+				dir = &FileNode{Name: part, Path: path, Dir: true}
+				dirs[path] = dir
+				parent.Children = append(parent.Children, dir)
+			}
+			parent = dir
+		}
+		parent.Children = append(parent.Children, &FileNode{Name: parts[len(parts)-1], Path: file})
+	}
+
+	sortFileNodes(root)
+	return root
+}
+
+// sortFileNodes 递归排序子节点，目录优先，同类按名称排序
+func sortFileNodes(node *FileNode) {
+	sort.SliceStable(node.Children, func(i, j int) bool {
+		a, b := node.Children[i], node.Children[j]
+		if a.Dir != b.Dir {
+			return a.Dir
+		}
+		return a.Name < b.Name
+	})
+	for _, child := range node.Children {
+		if child.Dir {
+			sortFileNodes(child)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestFilterChartFiles(t *testing.T) {
+	files := []string{"values.yaml", "templates/svc.yaml", "Chart.yaml", "templates/_helpers.tpl", "templates/tests/test.yaml", "templatesx.yaml"}
+
+	tests := []struct {
+		name   string
+		prefix string
+		want   []string
+	}{
+		{"no prefix sorts all", "", []string{"Chart.yaml", "templates/_helpers.tpl", "templates/svc.yaml", "templates/tests/test.yaml", "templatesx.yaml", "values.yaml"}},
+		{"directory prefix", "templates/", []string{"templates/_helpers.tpl", "templates/svc.yaml", "templates/tests/test.yaml"}},
+		{"nested prefix", "templates/tests/", []string{"templates/tests/test.yaml"}},
+		{"no match", "crds/", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterChartFiles(files, tt.prefix); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterChartFiles(%q) = %v, want %v", tt.prefix, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListChartFilesPage(t *testing.T) {
+	s := newRenderTestService(t, &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "files", Version: "0.1.0"},
+		Templates: []*chart.File{
+			{Name: "templates/a.yaml", Data: []byte("a: 1\n")},
+			{Name: "templates/b.yaml", Data: []byte("b: 1\n")},
+			{Name: "templates/c.yaml", Data: []byte("c: 1\n")},
+		},
+	})
+
+	tests := []struct {
+		name      string
+		opts      ChartFileOptions
+		wantFiles []string
+		wantTotal int
+	}{
+		{"first page", ChartFileOptions{Prefix: "templates/", Limit: 2}, []string{"templates/a.yaml", "templates/b.yaml"}, 3},
+		{"last page", ChartFileOptions{Prefix: "templates/", Limit: 2, Offset: 2}, []string{"templates/c.yaml"}, 3},
+		{"offset past end", ChartFileOptions{Prefix: "templates/", Limit: 2, Offset: 10}, []string{}, 3},
+		{"no limit", ChartFileOptions{Prefix: "templates/", Offset: 1}, []string{"templates/b.yaml", "templates/c.yaml"}, 3},
+		{"no prefix includes Chart.yaml", ChartFileOptions{Limit: 1}, []string{"Chart.yaml"}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := s.ListChartFilesPage("files", "0.1.0", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(list.Files, tt.wantFiles) || list.Total != tt.wantTotal {
+				t.Errorf("ListChartFilesPage() = %v (total %d), want %v (total %d)", list.Files, list.Total, tt.wantFiles, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestBuildFileTree(t *testing.T) {
+	got := buildFileTree([]string{"Chart.yaml", "templates/_helpers.tpl", "templates/tests/test.yaml", "templates/svc.yaml", "values.yaml"})
+
+	want := &FileNode{Dir: true, Children: []*FileNode{
+		{Name: "templates", Path: "templates", Dir: true, Children: []*FileNode{
+			{Name: "tests", Path: "templates/tests", Dir: true, Children: []*FileNode{
+				{Name: "test.yaml", Path: "templates/tests/test.yaml"},
+			}},
+			{Name: "_helpers.tpl", Path: "templates/_helpers.tpl"},
+			{Name: "svc.yaml", Path: "templates/svc.yaml"},
+		}},
+		{Name: "Chart.yaml", Path: "Chart.yaml"},
+		{Name: "values.yaml", Path: "values.yaml"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildFileTree() mismatch:\ngot  %s\nwant %s", fileTreeString(got), fileTreeString(want))
+	}
+}
+
+// fileTreeString 以缩进形式输出文件树，便于比较失败时查看
+func fileTreeString(node *FileNode) string {
+	var out string
+	var walk func(n *FileNode, indent string)
+	walk = func(n *FileNode, indent string) {
+		for _, child := range n.Children {
+			out += indent + child.Path + "\n"
+			walk(child, indent+"  ")
+		}
+	}
+	walk(node, "")
+	return out
+}
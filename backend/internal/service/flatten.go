@@ -0,0 +1,159 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 扁平化后 key 的大小写
+const (
+	FlattenCaseUpper    = "upper"
+	FlattenCaseLower    = "lower"
+	FlattenCasePreserve = "preserve"
+)
+
+// FlattenOptions 定义 values 扁平化时 key 的生成方式
+type FlattenOptions struct {
+	// Delimiter 为各级 key 之间的分隔符，默认为 "_"
+	Delimiter string
+	// Case 为 upper（默认）、lower 或 preserve
+	Case string
+}
+
+// ValidFlattenCase 判断扁平化的大小写方式是否受支持，空字符串视为 upper
+func ValidFlattenCase(c string) bool {
+	switch c {
+	case "", FlattenCaseUpper, FlattenCaseLower, FlattenCasePreserve:
+		return true
+	}
+	return false
+}
+
+// FlattenValues 将 Chart 默认 values 与 overrides 合并后扁平化为环境变量风格的 map，
+// 如 image.repository 生成 IMAGE_REPOSITORY，数组元素按下标生成 FOO_0
+func (s *HelmService) FlattenValues(name, version string, overrides map[string]interface{}) (map[string]string, error) {
+	return s.FlattenValuesWithOptions(name, version, overrides, FlattenOptions{})
+}
+
+// FlattenValuesWithOptions 按 opts 指定的分隔符与大小写扁平化 values
+func (s *HelmService) FlattenValuesWithOptions(name, version string, overrides map[string]interface{}, opts FlattenOptions) (map[string]string, error) {
+	values, err := s.GetChartValues(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Delimiter == "" {
+		opts.Delimiter = "_"
+	}
+
+	result := map[string]string{}
+	flattenValue(result, "", MergeValues(values, overrides), opts)
+	return result, nil
+}
+
+// flattenValue 将 value 以 prefix 为前缀写入 result
+func flattenValue(result map[string]string, prefix string, value interface{}, opts FlattenOptions) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			flattenValue(result, flattenKey(prefix, k, opts), child, opts)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenValue(result, flattenKey(prefix, strconv.Itoa(i), opts), child, opts)
+		}
+	case nil:
+		result[prefix] = ""
+	case string:
+		result[prefix] = v
+	case float64:
+		result[prefix] = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		result[prefix] = fmt.Sprint(v)
+	}
+}
+
+// flattenKey 拼接 key，非字母数字字符替换为 "_" 以生成合法的环境变量名
+func flattenKey(prefix, key string, opts FlattenOptions) string {
+	key = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, key)
+
+	switch opts.Case {
+	case FlattenCaseLower:
+		key = strings.ToLower(key)
+	case FlattenCasePreserve:
+	default:
+		key = strings.ToUpper(key)
+	}
+
+	if prefix == "" {
+		return key
+	}
+	return prefix + opts.Delimiter + key
+}
+
+// FormatEnv 将扁平化的 values 格式化为按 key 排序的 KEY=value 行，必要时对值加引号
+func FormatEnv(values map[string]string) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		v := values[k]
+		if strings.ContainsAny(v, " \t\n\"'#$\\`") {
+			v = strconv.Quote(v)
+		}
+		fmt.Fprintf(&b, "%s=%s\n", k, v)
+	}
+	return b.String()
+}
+
+// FlattenDotted 将嵌套的 values 扁平化为点号路径到值的 map，值保留原始类型：
+//   - map 的 key 以 "." 连接，key 本身含 "." 时转义为 "\."，与 helm --set 一致
+//   - 数组元素以下标表示，如 ports[0].name
+//   - 空 map 与空数组保留为 {} 与 []，不会丢失
+func FlattenDotted(values map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for k, v := range values {
+		flattenDotted(result, escapeDottedKey(k), v)
+	}
+	return result
+}
+
+// flattenDotted 将 value 以 prefix 为路径写入 result
+func flattenDotted(result map[string]interface{}, prefix string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			result[prefix] = v
+			return
+		}
+		for k, child := range v {
+			flattenDotted(result, prefix+"."+escapeDottedKey(k), child)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			result[prefix] = v
+			return
+		}
+		for i, child := range v {
+			flattenDotted(result, prefix+"["+strconv.Itoa(i)+"]", child)
+		}
+	default:
+		result[prefix] = v
+	}
+}
+
+// escapeDottedKey 转义 key 中的 "."
+func escapeDottedKey(key string) string {
+	return strings.ReplaceAll(key, ".", `\.`)
+}
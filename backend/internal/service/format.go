@@ -0,0 +1,105 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 重新序列化 manifest 时的集合风格
+const (
+	FlowStyleBlock = "block"
+	FlowStyleFlow  = "flow"
+)
+
+// FormatOptions 定义重新序列化 manifest 的格式，零值表示保持 helm 的原始输出
+type FormatOptions struct {
+	// Indent 为缩进空格数，0 表示使用默认的 2
+	Indent int
+	// FlowStyle 为 block 或 flow，空字符串表示保持各节点原有风格
+	FlowStyle string
+}
+
+// ValidFormatOptions 判断格式参数是否受支持，缩进范围为 2-8
+func ValidFormatOptions(opts FormatOptions) bool {
+	if opts.Indent != 0 && (opts.Indent < 2 || opts.Indent > 8) {
+		return false
+	}
+	return opts.FlowStyle == "" || opts.FlowStyle == FlowStyleBlock || opts.FlowStyle == FlowStyleFlow
+}
+
+// FormatManifest 按 opts 重新序列化 manifest 中的每个文档，注释（包括 # Source:）会被保留；
+// 无法解析的文档原样输出，并在返回的警告中说明
+func FormatManifest(manifest string, opts FormatOptions) (string, []string) {
+	indent := opts.Indent
+	if indent == 0 {
+		indent = 2
+	}
+
+	var b strings.Builder
+	var warnings []string
+	for _, doc := range splitManifests(manifest) {
+		formatted, err := formatDocument(doc, indent, opts.FlowStyle)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: left unformatted: %v", manifestSource(doc), err))
+			formatted = strings.TrimSpace(doc) + "\n"
+		}
+		b.WriteString("---\n")
+		b.WriteString(formatted)
+	}
+	return b.String(), warnings
+}
+
+// formatDocument 重新序列化单个 YAML 文档，开头的注释行（# Source:）原样保留在最前面，
+// 避免 flow 风格下被输出到花括号内
+func formatDocument(doc string, indent int, flowStyle string) (string, error) {
+	var header strings.Builder
+	lines := strings.Split(doc, "\n")
+	for len(lines) > 0 && (strings.HasPrefix(strings.TrimSpace(lines[0]), "#") || strings.TrimSpace(lines[0]) == "") {
+		if strings.TrimSpace(lines[0]) != "" {
+			header.WriteString(lines[0] + "\n")
+		}
+		lines = lines[1:]
+	}
+
+	// 拆分时去掉了文档末尾的换行，补回以免改变末尾 literal 块（|）的值
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(lines, "\n")+"\n"), &root); err != nil {
+		return "", err
+	}
+	// 只包含注释的文档没有内容节点，原样保留
+	if len(root.Content) == 0 {
+		return strings.TrimSpace(doc) + "\n", nil
+	}
+	setFlowStyle(&root, flowStyle)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indent)
+	if err := enc.Encode(&root); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return header.String() + buf.String(), nil
+}
+
+// setFlowStyle 递归设置 map 与数组节点的风格，flowStyle 为空时不做修改
+func setFlowStyle(node *yaml.Node, flowStyle string) {
+	if flowStyle == "" {
+		return
+	}
+	if node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode {
+		if flowStyle == FlowStyleFlow {
+			node.Style |= yaml.FlowStyle
+		} else {
+			node.Style &^= yaml.FlowStyle
+		}
+	}
+	for _, child := range node.Content {
+		setFlowStyle(child, flowStyle)
+	}
+}
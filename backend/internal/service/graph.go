@@ -0,0 +1,139 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// GraphNode 是依赖图中的一个 Chart
+type GraphNode struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Missing 为 true 表示该依赖在 Chart.yaml 中声明但未打包进 charts/ 目录，Version 为声明的版本约束
+	Missing bool `json:"missing,omitempty"`
+}
+
+// GraphEdge 是依赖图中父 Chart 指向子 Chart 的边
+type GraphEdge struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Alias     string `json:"alias,omitempty"`
+	Condition string `json:"condition,omitempty"`
+	// Cycle 为 true 表示该边会形成环，导出时不再沿其展开
+	Cycle bool `json:"cycle,omitempty"`
+}
+
+// GraphData 是 Chart 的递归依赖图，相同 name@version 的 Chart 只出现一次
+type GraphData struct {
+	Root  string      `json:"root"`
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// DependencyGraph 返回 Chart 的递归依赖图
+func (s *HelmService) DependencyGraph(name, version string) (GraphData, error) {
+	c, err := s.loadChart(name, version)
+	if err != nil {
+		return GraphData{}, err
+	}
+
+	g := &graphBuilder{
+		data:    GraphData{Root: graphNodeID(c.Metadata.Name, c.Metadata.Version), Nodes: []GraphNode{}, Edges: []GraphEdge{}},
+		visited: map[string]bool{},
+		onPath:  map[string]bool{},
+	}
+	g.visit(c)
+	return g.data, nil
+}
+
+// graphBuilder 深度优先遍历依赖树构建依赖图
+type graphBuilder struct {
+	data    GraphData
+	visited map[string]bool
+	onPath  map[string]bool
+}
+
+// visit 添加 Chart 节点并递归添加其依赖
+func (g *graphBuilder) visit(c *chart.Chart) {
+	id := graphNodeID(c.Metadata.Name, c.Metadata.Version)
+	if g.visited[id] {
+		return
+	}
+	g.visited[id] = true
+	g.onPath[id] = true
+	defer delete(g.onPath, id)
+
+	g.data.Nodes = append(g.data.Nodes, GraphNode{ID: id, Name: c.Metadata.Name, Version: c.Metadata.Version})
+
+	vendored := map[string]bool{}
+	for _, sub := range c.Dependencies() {
+		vendored[sub.Metadata.Name] = true
+
+		edge := GraphEdge{From: id, To: graphNodeID(sub.Metadata.Name, sub.Metadata.Version)}
+		if dep := findDependency(c, sub); dep != nil {
+			edge.Alias = dep.Alias
+			edge.Condition = dep.Condition
+		}
+		if g.onPath[edge.To] {
+			edge.Cycle = true
+			g.data.Edges = append(g.data.Edges, edge)
+			continue
+		}
+		g.data.Edges = append(g.data.Edges, edge)
+		g.visit(sub)
+	}
+
+	// 声明了但未打包的依赖
+	for _, dep := range c.Metadata.Dependencies {
+		if vendored[dep.Name] {
+			continue
+		}
+		to := graphNodeID(dep.Name, dep.Version)
+		if !g.visited[to] {
+			g.visited[to] = true
+			g.data.Nodes = append(g.data.Nodes, GraphNode{ID: to, Name: dep.Name, Version: dep.Version, Missing: true})
+		}
+		g.data.Edges = append(g.data.Edges, GraphEdge{From: id, To: to, Alias: dep.Alias, Condition: dep.Condition})
+	}
+}
+
+// graphNodeID 返回依赖图中节点的 ID
+func graphNodeID(name, version string) string {
+	return name + "@" + version
+}
+
+// DOT 将依赖图输出为 Graphviz 的 digraph
+func (g GraphData) DOT() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", strconv.Quote(g.Root))
+	for _, node := range g.Nodes {
+		attrs := []string{"label=" + strconv.Quote(node.Name+"\n"+node.Version)}
+		if node.Missing {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", strconv.Quote(node.ID), strings.Join(attrs, ", "))
+	}
+	for _, edge := range g.Edges {
+		var attrs []string
+		if edge.Alias != "" {
+			attrs = append(attrs, "label="+strconv.Quote(edge.Alias))
+		}
+		if edge.Condition != "" {
+			attrs = append(attrs, "tooltip="+strconv.Quote(edge.Condition))
+		}
+		if edge.Cycle {
+			attrs = append(attrs, "color=red")
+		}
+		fmt.Fprintf(&b, "  %s -> %s", strconv.Quote(edge.From), strconv.Quote(edge.To))
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -1,37 +1,212 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/cli"
-	"helm.sh/helm/v3/pkg/releaseutil"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
 // HelmService 处理 Helm 相关操作
 type HelmService struct {
 	chartsDir string
 	tempDir   string
+	dataDir   string
 	settings  *cli.EnvSettings
+	debug     bool
+
+	// namespaceDefaults 按命名空间注入的默认 values
+	namespaceDefaults map[string]map[string]interface{}
+	// renderLimiter 限制并发渲染数量
+	renderLimiter *renderLimiter
+
+	// defaultNamespace 未指定命名空间时使用的命名空间
+	defaultNamespace string
+	// releaseNameTemplate 未指定 release 名称时用于生成名称的模板
+	releaseNameTemplate *template.Template
+
+	// transformers 渲染前按注册顺序执行的 values 转换器
+	transformers   []ValueTransformer
+	transformersMu sync.RWMutex
+	// analyzerRules 在内置规则之外注册的渲染结果分析规则
+	analyzerRules   []AnalyzerRule
+	analyzerRulesMu sync.RWMutex
+
+	// maxVersionsPerChart 每个 Chart 保留的最大版本数，0 表示不清理
+	maxVersionsPerChart int
+
+	// digests 缓存 Chart 包的 SHA256 摘要
+	digests *digestCache
+	// metadata 缓存 Chart 包的元数据，用于列表过滤
+	metadata *metadataCache
+	// reproduciblePackaging 为 true 时打包结果逐字节可复现
+	reproduciblePackaging bool
+	// cas 非空时按内容摘要存储 Chart 包
+	cas *casStore
+	// renderTimeout 默认的渲染超时，0 表示不限制，可由 Chart 注解覆盖
+	renderTimeout time.Duration
+	// chartDirs 以别名注册的未打包 Chart 目录
+	chartDirs *chartDirs
+	// renderObserver 非空时在每次渲染结束后调用，用于统计指标
+	renderObserver RenderObserver
+	// tempMaxAge 临时目录中的文件被清理前保留的时长
+	tempMaxAge time.Duration
+	// uploadLocks 按上传 ID 串行化分片写入，值为 *sync.Mutex
+	uploadLocks sync.Map
+
+	// sessions 保存用户上传的 kubeconfig 会话
+	sessions *clusterSessions
+	// clientGetter 非空时替代默认 kubeconfig 访问集群，用于会话级的服务副本
+	clientGetter genericclioptions.RESTClientGetter
 }
 
 // NewHelmService 创建新的 Helm 服务
 func NewHelmService() *HelmService {
-	return &HelmService{
+	s := &HelmService{
 		chartsDir: "../charts",
 		tempDir:   "../temp",
+		dataDir:   "../data",
 		settings:  cli.New(),
+		debug:     os.Getenv("HELM_UI_DEBUG") == "true",
+		digests:   &digestCache{},
+		metadata:  &metadataCache{},
+		chartDirs: &chartDirs{},
+		sessions:  newClusterSessions(),
 	}
+
+	// 默认命名空间与 release 名称模板
+	s.defaultNamespace = os.Getenv("HELM_UI_DEFAULT_NAMESPACE")
+	if s.defaultNamespace == "" {
+		s.defaultNamespace = "default"
+	}
+	s.releaseNameTemplate = loadReleaseNameTemplate()
+
+	// 并发渲染上限默认为 CPU 核数，排队上限默认为并发上限的 4 倍
+	maxConcurrent := envInt("HELM_UI_MAX_CONCURRENT_RENDERS", defaultMaxConcurrentRenders())
+	s.renderLimiter = newRenderLimiter(maxConcurrent, envInt("HELM_UI_MAX_QUEUED_RENDERS", maxConcurrent*4))
+
+	// 每个 Chart 保留的最大版本数，默认不清理
+	s.maxVersionsPerChart = envInt("HELM_UI_MAX_VERSIONS_PER_CHART", 0)
+	s.reproduciblePackaging = os.Getenv("HELM_UI_REPRODUCIBLE_PACKAGING") == "true"
+	s.renderTimeout = loadRenderTimeout()
+	s.tempMaxAge = loadTempMaxAge()
+
+	// 内容寻址存储，默认按名称存储
+	if os.Getenv("HELM_UI_CAS") == "true" {
+		s.cas = newCASStore(s.chartsDir)
+	}
+
+	// 加载命名空间默认 values，未配置时不做任何注入
+	if path := os.Getenv("HELM_UI_NS_DEFAULTS"); path != "" {
+		defaults, err := loadNamespaceDefaults(path)
+		if err != nil {
+			log.Printf("ignoring namespace defaults: %v", err)
+		} else {
+			s.namespaceDefaults = defaults
+		}
+	}
+
+	return s
+}
+
+// chartPath 返回指定 Chart 版本的包路径，兼容以 .tar.gz 结尾存储的旧包；
+// 启用内容寻址存储时优先通过索引解析
+func (s *HelmService) chartPath(name, version string) string {
+	if s.cas != nil {
+		if digest, ok := s.cas.resolve(name, version); ok {
+			return s.cas.blobPath(digest)
+		}
+	}
+
+	path := filepath.Join(s.chartsDir, fmt.Sprintf("%s-%s.tgz", name, version))
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		legacy := filepath.Join(s.chartsDir, fmt.Sprintf("%s-%s.tar.gz", name, version))
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy
+		}
+	}
+	return path
+}
+
+// isChartArchive 判断文件名是否为 Chart 包（.tgz 或 .tar.gz）
+func isChartArchive(filename string) bool {
+	return strings.HasSuffix(filename, ".tgz") || strings.HasSuffix(filename, ".tar.gz")
+}
+
+// normalizeChartFilename 将 .tar.gz 结尾的 Chart 包文件名统一为 .tgz
+func normalizeChartFilename(filename string) string {
+	if strings.HasSuffix(filename, ".tar.gz") {
+		return strings.TrimSuffix(filename, ".tar.gz") + ".tgz"
+	}
+	return filename
+}
+
+// ChartArchivePath 返回已存储 Chart 包的路径，不存在时返回 ErrChartNotFound
+func (s *HelmService) ChartArchivePath(name, version string) (string, error) {
+	path := s.chartPath(name, version)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: %s-%s", ErrChartNotFound, name, version)
+		}
+		return "", fmt.Errorf("failed to stat chart: %w", err)
+	}
+	return path, nil
 }
 
-// PackageChart 将 Chart 目录打包成 tgz 文件
+// loadChart 加载指定版本的 Chart，名称为已注册的目录别名时直接加载目录
+func (s *HelmService) loadChart(name, version string) (*chart.Chart, error) {
+	if dir, ok := s.registeredChartDir(name); ok {
+		chart, err := loader.LoadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s (%s): %v", ErrChartNotFound, name, dir, err)
+		}
+		return chart, nil
+	}
+
+	chart, err := loader.Load(s.chartPath(name, version))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s-%s: %v", ErrChartNotFound, name, version, err)
+	}
+	return chart, nil
+}
+
+// newActionConfig 创建指定命名空间的 action 配置
+func (s *HelmService) newActionConfig(namespace string) (*action.Configuration, error) {
+	return s.newActionConfigWithLog(namespace, nil)
+}
+
+// newActionConfigWithLog 创建 action 配置，并将 helm 的调试日志输出到 log
+func (s *HelmService) newActionConfigWithLog(namespace string, log action.DebugLog) (*action.Configuration, error) {
+	actionConfig := new(action.Configuration)
+	if err := actionConfig.Init(s.restClientGetter(), namespace, os.Getenv("HELM_DRIVER"), log); err != nil {
+		return nil, fmt.Errorf("%w: failed to init action config: %v", ErrClusterUnavailable, err)
+	}
+	return actionConfig, nil
+}
+
+// PackageChart 将 Chart 目录打包成 tgz 文件，.helmignore 排除的文件不会被打包
 func (s *HelmService) PackageChart(chartDir string) (string, error) {
+	// 拒绝包含符号链接的目录
+	if err := checkDirSymlinks(chartDir); err != nil {
+		return "", err
+	}
+
 	// 加载 Chart
 	chart, err := loader.Load(chartDir)
 	if err != nil {
@@ -52,10 +227,17 @@ func (s *HelmService) PackageChart(chartDir string) (string, error) {
 		return "", fmt.Errorf("failed to package chart: %w", err)
 	}
 
+	// 可复现打包模式下规范化包内容，保证摘要稳定
+	if s.reproduciblePackaging {
+		if err := normalizeArchive(packagedFilePath); err != nil {
+			return "", err
+		}
+	}
+
 	return packagedFilePath, nil
 }
 
-// UploadChartDir 上传并打包 Chart 目录
+// UploadChartDir 上传并打包 Chart 目录，.helmignore 排除的文件不会被打包
 func (s *HelmService) UploadChartDir(chartDir string) error {
 	// 打包 Chart
 	packagedFilePath, err := s.PackageChart(chartDir)
@@ -93,8 +275,19 @@ func (s *HelmService) UploadChart(chartFile io.Reader, filename string) error {
 		return fmt.Errorf("failed to create charts directory: %w", err)
 	}
 
-	// 创建目标文件
-	dst, err := os.Create(filepath.Join(s.chartsDir, filename))
+	// 内容寻址存储按摘要保存
+	if s.cas != nil {
+		path, err := s.cas.put(chartFile)
+		if err != nil {
+			return err
+		}
+		s.enforceRetention(path)
+		return nil
+	}
+
+	// 创建目标文件，.tar.gz 统一保存为 .tgz
+	path := filepath.Join(s.chartsDir, normalizeChartFilename(filename))
+	dst, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("failed to create chart file: %w", err)
 	}
@@ -104,29 +297,170 @@ func (s *HelmService) UploadChart(chartFile io.Reader, filename string) error {
 	if _, err := io.Copy(dst, chartFile); err != nil {
 		return fmt.Errorf("failed to copy chart file: %w", err)
 	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("failed to write chart file: %w", err)
+	}
+
+	// 按保留策略清理旧版本
+	s.enforceRetention(path)
 
 	return nil
 }
 
-// ListCharts 列出所有可用的 Charts
-func (s *HelmService) ListCharts() ([]string, error) {
+// ListCharts 列出满足过滤条件的 Charts
+func (s *HelmService) ListCharts(opts ChartListOptions) ([]string, error) {
+	charts, err := s.listCharts(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	filenames := make([]string, 0, len(charts))
+	for _, c := range charts {
+		filenames = append(filenames, c.filename)
+	}
+	return filenames, nil
+}
+
+// ChartEntry 是 Chart 列表中的一项，包含从缓存的元数据中读取的名称、版本与废弃状态
+type ChartEntry struct {
+	Filename string `json:"filename"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	// Deprecated 显式输出，不随 omitempty 省略
+	Deprecated bool `json:"deprecated"`
+}
+
+// ListChartEntries 与 ListCharts 顺序相同地列出 Chart，每一项附带名称、版本与废弃状态。
+// 无法读取元数据的包以文件名作为名称，版本为空
+func (s *HelmService) ListChartEntries(opts ChartListOptions) ([]ChartEntry, error) {
+	charts, err := s.listCharts(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]ChartEntry, 0, len(charts))
+	for _, c := range charts {
+		entries = append(entries, ChartEntry{Filename: c.filename, Name: c.name, Version: c.rawVersion, Deprecated: c.deprecated})
+	}
+	return entries, nil
+}
+
+// listCharts 列出满足过滤条件的 Chart 包并排序
+func (s *HelmService) listCharts(opts ChartListOptions) ([]listedChart, error) {
+	if s.cas != nil {
+		return s.listChartsCAS(opts)
+	}
+
 	files, err := os.ReadDir(s.chartsDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read charts directory: %w", err)
 	}
 
-	var charts []string
+	var charts []listedChart
 	for _, file := range files {
-		if !file.IsDir() && filepath.Ext(file.Name()) == ".tgz" {
-			charts = append(charts, file.Name())
+		if !file.IsDir() && isChartArchive(file.Name()) {
+			path := filepath.Join(s.chartsDir, file.Name())
+			if !s.includeArchive(path, opts) {
+				continue
+			}
+			// 无法读取元数据的包以文件名作为名称参与排序
+			listed := listedChart{filename: file.Name(), name: file.Name()}
+			if metadata, err := s.archiveMetadata(path); err == nil {
+				listed.name = metadata.Name
+				listed.rawVersion = metadata.Version
+				listed.version, _ = semver.NewVersion(metadata.Version)
+				listed.deprecated = metadata.Deprecated
+			}
+			charts = append(charts, listed)
 		}
 	}
 
+	orderListedCharts(charts)
 	return charts, nil
 }
 
+// listedChart 是 Chart 列表中的一项及其排序依据
+type listedChart struct {
+	filename string
+	name     string
+	// version 为 nil 表示版本不是合法的语义化版本
+	version *semver.Version
+	// rawVersion 为元数据中的原始版本号
+	rawVersion string
+	deprecated bool
+}
+
+// sortListedCharts 排序并返回文件名，排序规则见 orderListedCharts
+func sortListedCharts(charts []listedChart) []string {
+	orderListedCharts(charts)
+
+	filenames := make([]string, 0, len(charts))
+	for _, c := range charts {
+		filenames = append(filenames, c.filename)
+	}
+	return filenames
+}
+
+// orderListedCharts 按名称升序、同名按语义化版本降序排序，使列表结果与目录读取顺序无关；
+// 版本无法解析的包排在同名 Chart 之后，最终按文件名排序
+func orderListedCharts(charts []listedChart) {
+	sort.Slice(charts, func(i, j int) bool {
+		a, b := charts[i], charts[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if a.version != nil && b.version != nil && !a.version.Equal(b.version) {
+			return a.version.GreaterThan(b.version)
+		}
+		if (a.version == nil) != (b.version == nil) {
+			return a.version != nil
+		}
+		return a.filename < b.filename
+	})
+}
+
+// ChartsLastModified 返回 charts 目录中最近的修改时间：取目录本身（反映文件的增删）与其中各文件 mtime 的最大值，
+// 只读取文件状态而不加载 Chart
+func (s *HelmService) ChartsLastModified() (time.Time, error) {
+	info, err := os.Stat(s.chartsDir)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to stat charts directory: %w", err)
+	}
+	latest := info.ModTime()
+
+	files, err := os.ReadDir(s.chartsDir)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read charts directory: %w", err)
+	}
+	for _, file := range files {
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
+
 // ListChartVersions 列出指定 Chart 的所有版本
 func (s *HelmService) ListChartVersions(name string) ([]string, error) {
+	if s.cas != nil {
+		entries, err := s.cas.entries()
+		if err != nil {
+			return nil, err
+		}
+		var versions []string
+		for _, entry := range entries {
+			if entry.name == name {
+				versions = append(versions, fmt.Sprintf("%s-%s.tgz", entry.name, entry.version))
+			}
+		}
+		return versions, nil
+	}
+
 	files, err := os.ReadDir(s.chartsDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read charts directory: %w", err)
@@ -134,7 +468,7 @@ func (s *HelmService) ListChartVersions(name string) ([]string, error) {
 
 	var versions []string
 	for _, file := range files {
-		if !file.IsDir() && filepath.Ext(file.Name()) == ".tgz" && filepath.Base(file.Name()) == name {
+		if !file.IsDir() && isChartArchive(file.Name()) && filepath.Base(file.Name()) == name {
 			versions = append(versions, file.Name())
 		}
 	}
@@ -142,33 +476,223 @@ func (s *HelmService) ListChartVersions(name string) ([]string, error) {
 	return versions, nil
 }
 
+// ChartMetadata 描述 Chart 的元数据
+type ChartMetadata struct {
+	*chart.Metadata
+	// Deprecated 显式输出，不随 omitempty 省略
+	Deprecated bool `json:"deprecated"`
+	// Annotations 显式输出，没有注解时为空对象
+	Annotations map[string]string `json:"annotations"`
+}
+
+// GetChartMetadata 获取指定 Chart 的元数据
+func (s *HelmService) GetChartMetadata(name, version string) (*ChartMetadata, error) {
+	// 加载 Chart
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := chart.Metadata.Annotations
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	return &ChartMetadata{Metadata: chart.Metadata, Deprecated: chart.Metadata.Deprecated, Annotations: annotations}, nil
+}
+
+// chartWarnings 返回渲染 Chart 时需要提示给用户的警告
+func chartWarnings(chart *chart.Chart) []string {
+	var warnings []string
+	if chart.Metadata.Deprecated {
+		warnings = append(warnings, "chart is deprecated")
+	}
+	return warnings
+}
+
 // GetChartValues 获取指定 Chart 的 values
 func (s *HelmService) GetChartValues(name, version string) (map[string]interface{}, error) {
-	chartPath := filepath.Join(s.chartsDir, fmt.Sprintf("%s-%s.tgz", name, version))
-
 	// 加载 Chart
-	chart, err := loader.Load(chartPath)
+	chart, err := s.loadChart(name, version)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load chart: %w", err)
+		return nil, err
 	}
 
 	return chart.Values, nil
 }
 
+// GetRawValues 返回 Chart 中 values.yaml 的原始内容，保留注释与 key 的顺序
+func (s *HelmService) GetRawValues(name, version string) ([]byte, error) {
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range chart.Raw {
+		if f.Name == "values.yaml" {
+			return f.Data, nil
+		}
+	}
+	return []byte{}, nil
+}
+
+// RenderOptions 定义渲染 Chart 时的可选参数
+type RenderOptions struct {
+	// SelectedFiles 仅保留来自这些模板文件的 manifest
+	SelectedFiles []string
+	// Resources 仅保留匹配 kind/name 的资源，例如 Deployment/web
+	Resources []string
+	// DryRunMode 为 client（默认）或 server
+	DryRunMode string
+	// Tags 覆盖 values 中的 tags，用于按标签启用或禁用子 Chart
+	Tags map[string]bool
+	// Subcharts 按子 Chart 名称（或别名）启用或禁用子 Chart
+	Subcharts map[string]bool
+	// NoHooks 为 true 时渲染结果中不包含 hook 资源
+	NoHooks bool
+	// ArrayMerge 指定用户 values 与 Chart 默认 values 合并时数组的处理方式
+	ArrayMerge ArrayMergeOptions
+	// Debug 为 true 时渲染失败返回 RenderDebugError，包含详细错误、调试日志和部分渲染结果
+	Debug bool
+	// SortOrder 为 none（默认）或 install，install 时按 helm 安装顺序排序输出
+	SortOrder string
+	// ReleaseService 覆盖模板中的 .Release.Service，为空或 Helm 时与 helm 一致
+	ReleaseService string
+	// Deterministic 为 true 时冻结 now、randAlphaNum 等函数，使重复渲染的结果一致，见 FrozenFunctions
+	Deterministic bool
+}
+
+// 支持的 dry-run 模式
+const (
+	// DryRunClient 仅在本地渲染，不访问集群
+	DryRunClient = "client"
+	// DryRunServer 通过集群执行 dry-run，会经过准入 webhook
+	DryRunServer = "server"
+)
+
+// prepareValues 合并命名空间默认 values 并执行已注册的转换器，用户提供的值优先于默认值
+func (s *HelmService) prepareValues(chartName, namespace string, values map[string]interface{}) (map[string]interface{}, error) {
+	values = MergeValues(s.NamespaceDefaults(namespace), values)
+	return s.applyTransformers(chartName, values)
+}
+
+// RenderResult 是渲染 Chart 的结果
+type RenderResult struct {
+	// Manifest 为渲染（并过滤）后的 manifest
+	Manifest string
+	// Warnings 为不影响渲染结果的提示信息
+	Warnings []string
+	// Errors 为 best-effort 模式下渲染失败的模板
+	Errors []TemplateError
+	// Subcharts 为各直接依赖在本次渲染中的启用状态
+	Subcharts map[string]bool
+	// UnmatchedFiles 为没有产生任何 manifest 的 SelectedFiles
+	UnmatchedFiles []string
+}
+
 // RenderChart 渲染 Chart
-func (s *HelmService) RenderChart(name, version string, values map[string]interface{}, releaseName, namespace string, selectedFiles []string) (string, error) {
-	chartPath := filepath.Join(s.chartsDir, fmt.Sprintf("%s-%s.tgz", name, version))
+func (s *HelmService) RenderChart(name, version string, values map[string]interface{}, releaseName, namespace string, opts RenderOptions) (*RenderResult, error) {
+	return s.RenderChartContext(context.Background(), name, version, values, releaseName, namespace, opts)
+}
+
+// RenderChartContext 渲染 Chart，ctx 取消时立即返回，渲染与超时一样在后台继续运行直至结束
+func (s *HelmService) RenderChartContext(ctx context.Context, name, version string, values map[string]interface{}, releaseName, namespace string, opts RenderOptions) (result *RenderResult, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// 限制并发渲染，避免大量请求同时解压和渲染 Chart 导致内存耗尽
+	if err := s.renderLimiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	// 超时被放弃的渲染在后台结束后才释放名额
+	abandoned := false
+	defer func() {
+		if !abandoned {
+			s.renderLimiter.release()
+		}
+	}()
+	// 耗时从获得名额开始计算，不包括排队时间
+	defer s.observeRender(name, version, time.Now(), &err)
 
 	// 加载 Chart
-	chart, err := loader.Load(chartPath)
+	chart, err := s.loadChart(name, version)
 	if err != nil {
-		return "", fmt.Errorf("failed to load chart: %w", err)
+		return nil, err
+	}
+
+	// 缺少必需依赖时直接给出明确的错误，而不是模板渲染错误
+	if err := checkDependencies(chart); err != nil {
+		return nil, err
+	}
+
+	if opts.Deterministic {
+		freezeChartTemplates(chart)
+	}
+
+	// 依赖处理会移除被禁用的子 Chart，需提前记录声明的依赖
+	declared := declaredSubcharts(chart)
+	warnings := append(chartWarnings(chart), valueTypeWarnings(chart, values)...)
+
+	rel, abandoned, err := s.renderReleaseWithTimeout(ctx, chart, values, releaseName, namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// 与 helm template 一致，默认在 manifest 之后输出 hook 资源
+	manifest := rel.Manifest
+	if !opts.NoHooks {
+		manifest = appendHooks(manifest, rel.Hooks)
+	}
+
+	// 按指定的文件和资源过滤渲染结果
+	result = &RenderResult{
+		Manifest:       sortManifests(filterManifests(manifest, chart.Metadata.Name, opts.SelectedFiles, opts.Resources), opts.SortOrder),
+		Warnings:       warnings,
+		Subcharts:      subchartStatus(chart, declared),
+		UnmatchedFiles: unmatchedFiles(manifest, chart.Metadata.Name, opts.SelectedFiles),
+	}
+
+	return result, nil
+}
+
+// applyArrayMerge 按数组合并策略预先将用户 values 合并到 Chart 默认 values 上，
+// replace 策略下保持 helm 原有的合并行为
+func applyArrayMerge(chart *chart.Chart, values map[string]interface{}, opts ArrayMergeOptions) map[string]interface{} {
+	if opts.Strategy == "" || opts.Strategy == ArrayMergeReplace {
+		return values
+	}
+	return MergeValuesWithStrategy(chart.Values, values, opts)
+}
+
+// appendHooks 将 hook 资源追加到 manifest 之后
+func appendHooks(manifest string, hooks []*release.Hook) string {
+	var b strings.Builder
+	b.WriteString(manifest)
+	for _, hook := range hooks {
+		fmt.Fprintf(&b, "---\n# Source: %s\n%s\n", hook.Path, hook.Manifest)
+	}
+	return b.String()
+}
+
+// renderRelease 以 dry-run 方式安装 Chart，返回包含 manifest 与 hook 的 release
+func (s *HelmService) renderRelease(chart *chart.Chart, values map[string]interface{}, releaseName, namespace string, opts RenderOptions) (*release.Release, error) {
+	// helm 的 install 动作固定 .Release.Service 为 Helm，需要覆盖时直接使用模板引擎渲染
+	if opts.ReleaseService != "" && opts.ReleaseService != DefaultReleaseService {
+		return s.renderReleaseWithService(chart, values, releaseName, namespace, opts)
+	}
+
+	// debug 模式下收集 helm 的调试日志
+	var debugLog *debugCollector
+	var logFn action.DebugLog
+	if opts.Debug {
+		debugLog = &debugCollector{}
+		logFn = debugLog.logf
 	}
 
 	// 创建 action 配置
-	actionConfig := new(action.Configuration)
-	if err := actionConfig.Init(s.settings.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER"), nil); err != nil {
-		return "", fmt.Errorf("failed to init action config: %w", err)
+	actionConfig, err := s.newActionConfigWithLog(namespace, logFn)
+	if err != nil {
+		return nil, err
 	}
 
 	// 创建模板动作
@@ -177,44 +701,48 @@ func (s *HelmService) RenderChart(name, version string, values map[string]interf
 	client.ReleaseName = releaseName
 	client.Namespace = namespace
 	client.Replace = true
-	client.ClientOnly = true
+	client.DisableHooks = opts.NoHooks
+
+	switch opts.DryRunMode {
+	case "", DryRunClient:
+		client.ClientOnly = true
+	case DryRunServer:
+		// server 模式需要集群可达
+		if _, err := s.kubeClient(); err != nil {
+			return nil, err
+		}
+		client.DryRunOption = DryRunServer
+	default:
+		return nil, fmt.Errorf("unsupported dry run mode: %s", opts.DryRunMode)
+	}
 
-	// 渲染 Chart
-	rel, err := client.Run(chart, values)
+	values, err = s.prepareValues(chart.Metadata.Name, namespace, values)
 	if err != nil {
-		return "", fmt.Errorf("failed to render chart: %w", err)
+		return nil, err
 	}
+	values = applyArrayMerge(chart, values, opts.ArrayMerge)
 
-	// 如果指定了文件列表，过滤渲染结果
-	if len(selectedFiles) > 0 {
-		var filteredManifests []string
-		manifests := releaseutil.SplitManifests(rel.Manifest)
+	// 请求中显式指定的子 Chart 开关优先于 values
+	values = MergeValues(values, applySubchartToggles(chart, opts.Tags, opts.Subcharts))
 
-		for _, selectedFile := range selectedFiles {
-			for _, manifest := range manifests {
-				// 构建完整的文件路径模式
-				fullPath := fmt.Sprintf("%s/%s", chart.Metadata.Name, selectedFile)
-				if strings.Contains(manifest, fmt.Sprintf("# Source: %s", fullPath)) {
-					filteredManifests = append(filteredManifests, manifest)
-					break
-				}
-			}
+	// 渲染 Chart
+	rel, err := client.Run(chart, values)
+	if err != nil {
+		if opts.Debug {
+			return nil, newRenderDebugError(err, rel, debugLog)
 		}
-
-		return strings.Join(filteredManifests, "\n---\n"), nil
+		return nil, fmt.Errorf("%w: %v", ErrRenderFailed, err)
 	}
 
-	return rel.Manifest, nil
+	return rel, nil
 }
 
 // ListChartFiles 列出指定 Chart 包含的文件
 func (s *HelmService) ListChartFiles(name, version string) ([]string, error) {
-	chartPath := filepath.Join(s.chartsDir, fmt.Sprintf("%s-%s.tgz", name, version))
-
 	// 加载 Chart
-	chart, err := loader.Load(chartPath)
+	chart, err := s.loadChart(name, version)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load chart: %w", err)
+		return nil, err
 	}
 
 	var files []string
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// newRenderTestService 创建使用临时 Chart 目录的服务，并保存给定的 Chart
+func newRenderTestService(t *testing.T, charts ...*chart.Chart) *HelmService {
+	t.Helper()
+
+	s := NewHelmService()
+	s.chartsDir = t.TempDir()
+	s.tempDir = t.TempDir()
+	for _, c := range charts {
+		if _, err := chartutil.Save(c, s.chartsDir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestRenderChartHooks(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "hooks", Version: "0.1.0"},
+		Templates: []*chart.File{
+			{Name: "templates/cm.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n")},
+			{Name: "templates/job.yaml", Data: []byte("apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: migrate\n  annotations:\n    \"helm.sh/hook\": pre-install\n")},
+			{Name: "templates/test.yaml", Data: []byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: smoke\n  annotations:\n    \"helm.sh/hook\": test\n")},
+		},
+	}
+	s := newRenderTestService(t, c)
+
+	tests := []struct {
+		name      string
+		noHooks   bool
+		wantHooks bool
+	}{
+		{"hooks included by default", false, true},
+		{"noHooks drops hook resources", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.RenderChart("hooks", "0.1.0", nil, "r", "default", RenderOptions{NoHooks: tt.noHooks})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(result.Manifest, "# Source: hooks/templates/cm.yaml") {
+				t.Errorf("manifest is missing the regular resource:\n%s", result.Manifest)
+			}
+			for _, hook := range []string{"hooks/templates/job.yaml", "hooks/templates/test.yaml"} {
+				if got := strings.Contains(result.Manifest, "# Source: "+hook); got != tt.wantHooks {
+					t.Errorf("hook %s present = %v, want %v", hook, got, tt.wantHooks)
+				}
+			}
+			// 与 helm template 一致，hook 位于普通资源之后
+			if tt.wantHooks && strings.Index(result.Manifest, "templates/job.yaml") < strings.Index(result.Manifest, "templates/cm.yaml") {
+				t.Error("hooks should be rendered after regular resources")
+			}
+		})
+	}
+}
+
+func TestChartArchiveNames(t *testing.T) {
+	tests := []struct {
+		filename   string
+		archive    bool
+		normalized string
+	}{
+		{"app-1.0.0.tgz", true, "app-1.0.0.tgz"},
+		{"app-1.0.0.tar.gz", true, "app-1.0.0.tgz"},
+		{"app-1.0.0.tar", false, "app-1.0.0.tar"},
+		{"app-1.0.0.gz", false, "app-1.0.0.gz"},
+		{"values.yaml", false, "values.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if got := isChartArchive(tt.filename); got != tt.archive {
+				t.Errorf("isChartArchive() = %v, want %v", got, tt.archive)
+			}
+			if got := normalizeChartFilename(tt.filename); got != tt.normalized {
+				t.Errorf("normalizeChartFilename() = %q, want %q", got, tt.normalized)
+			}
+		})
+	}
+}
+
+func TestTarGzChartArchives(t *testing.T) {
+	// packageChart 打包一个最小的 Chart 并返回包内容
+	packageChart := func(name string) []byte {
+		t.Helper()
+		c := &chart.Chart{
+			Metadata:  &chart.Metadata{APIVersion: chart.APIVersionV2, Name: name, Version: "0.1.0"},
+			Templates: []*chart.File{{Name: "templates/cm.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n")}},
+		}
+		path, err := chartutil.Save(c, t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	s := newRenderTestService(t)
+	// uploaded 通过上传保存，legacy 是直接放在目录中的旧 .tar.gz 包
+	if err := s.UploadChart(bytes.NewReader(packageChart("uploaded")), "uploaded-0.1.0.tar.gz"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.chartsDir, "legacy-0.1.0.tar.gz"), packageChart("legacy"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	charts, err := s.ListCharts(ChartListOptions{IncludeDeprecated: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"legacy-0.1.0.tar.gz", "uploaded-0.1.0.tgz"}; !reflect.DeepEqual(charts, want) {
+		t.Errorf("ListCharts() = %v, want %v", charts, want)
+	}
+
+	for _, name := range []string{"uploaded", "legacy"} {
+		t.Run(name, func(t *testing.T) {
+			result, err := s.RenderChart(name, "0.1.0", nil, "r", "default", RenderOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(result.Manifest, "name: "+name) {
+				t.Errorf("unexpected manifest:\n%s", result.Manifest)
+			}
+		})
+	}
+}
+
+func TestRenderChartGlobalValues(t *testing.T) {
+	cm := func(name string) []byte {
+		return []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\ndata:\n  foo: {{ .Values.global.foo | quote }}\n")
+	}
+	sub := &chart.Chart{
+		Metadata:  &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "sub", Version: "1.0.0"},
+		Templates: []*chart.File{{Name: "templates/cm.yaml", Data: cm("sub")}},
+	}
+	parent := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2, Name: "parent", Version: "0.1.0",
+			Dependencies: []*chart.Dependency{{Name: "sub", Version: "1.0.0"}},
+		},
+		Templates: []*chart.File{{Name: "templates/cm.yaml", Data: cm("parent")}},
+		Raw:       []*chart.File{{Name: "values.yaml", Data: []byte("global:\n  foo: default\n")}},
+	}
+	parent.SetDependencies(sub)
+	s := newRenderTestService(t, parent)
+
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		want   string
+	}{
+		{"parent default propagated", nil, `foo: "default"`},
+		{"request global propagated", map[string]interface{}{"global": map[string]interface{}{"foo": "bar"}}, `foo: "bar"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.RenderChart("parent", "0.1.0", tt.values, "r", "default", RenderOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, source := range []string{"parent/templates/cm.yaml", "parent/charts/sub/templates/cm.yaml"} {
+				doc := manifestFromSource(t, result.Manifest, source)
+				if !strings.Contains(doc, tt.want) {
+					t.Errorf("%s does not contain %s:\n%s", source, tt.want, doc)
+				}
+			}
+		})
+	}
+}
+
+// manifestFromSource 返回渲染结果中来自指定模板的文档
+func manifestFromSource(t *testing.T, manifest, source string) string {
+	t.Helper()
+	for _, doc := range splitManifests(manifest) {
+		if strings.Contains(doc, "# Source: "+source+"\n") {
+			return doc
+		}
+	}
+	t.Fatalf("no document from %s in:\n%s", source, manifest)
+	return ""
+}
+
+func TestChartsLastModified(t *testing.T) {
+	s := newRenderTestService(t)
+	old := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := old.Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		change func(t *testing.T)
+		want   time.Time
+	}{
+		{"directory mtime", func(t *testing.T) {}, old},
+		{"newer file", func(t *testing.T) {
+			path := filepath.Join(s.chartsDir, "app-0.1.0.tgz")
+			if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(path, newer, newer); err != nil {
+				t.Fatal(err)
+			}
+		}, newer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change(t)
+			// 目录本身的 mtime 固定为 old，只观察文件的影响
+			if err := os.Chtimes(s.chartsDir, old, old); err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.ChartsLastModified()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ChartsLastModified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortListedCharts(t *testing.T) {
+	listed := func(filename, name, version string) listedChart {
+		v, _ := semver.NewVersion(version)
+		return listedChart{filename: filename, name: name, version: v}
+	}
+	charts := []listedChart{
+		listed("web-1.2.0.tgz", "web", "1.2.0"),
+		listed("api-0.9.0.tgz", "api", "0.9.0"),
+		listed("web-1.10.0.tgz", "web", "1.10.0"),
+		listed("web-latest.tgz", "web", "latest"),
+		listed("api-1.0.0-rc.1.tgz", "api", "1.0.0-rc.1"),
+		listed("api-1.0.0.tgz", "api", "1.0.0"),
+		listed("broken.tgz", "broken.tgz", ""),
+		listed("web-nightly.tgz", "web", "nightly"),
+	}
+	// 名称升序，同名按语义化版本降序，无法解析的版本排在最后并按文件名排序
+	want := []string{
+		"api-1.0.0.tgz", "api-1.0.0-rc.1.tgz", "api-0.9.0.tgz",
+		"broken.tgz",
+		"web-1.10.0.tgz", "web-1.2.0.tgz", "web-latest.tgz", "web-nightly.tgz",
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		shuffled := append([]listedChart(nil), charts...)
+		r.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		if got := sortListedCharts(shuffled); !reflect.DeepEqual(got, want) {
+			t.Fatalf("sortListedCharts() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestListChartsStableOrder(t *testing.T) {
+	versions := []string{"0.1.0", "0.10.0", "0.2.0", "1.0.0"}
+	var charts []*chart.Chart
+	for _, name := range []string{"zeta", "alpha", "mid"} {
+		for _, version := range versions {
+			charts = append(charts, &chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: name, Version: version}})
+		}
+	}
+	// 以随机顺序创建文件，列表结果不应依赖目录读取顺序
+	rand.New(rand.NewSource(1)).Shuffle(len(charts), func(i, j int) { charts[i], charts[j] = charts[j], charts[i] })
+	s := newRenderTestService(t, charts...)
+
+	want := []string{
+		"alpha-1.0.0.tgz", "alpha-0.10.0.tgz", "alpha-0.2.0.tgz", "alpha-0.1.0.tgz",
+		"mid-1.0.0.tgz", "mid-0.10.0.tgz", "mid-0.2.0.tgz", "mid-0.1.0.tgz",
+		"zeta-1.0.0.tgz", "zeta-0.10.0.tgz", "zeta-0.2.0.tgz", "zeta-0.1.0.tgz",
+	}
+	for i := 0; i < 3; i++ {
+		got, err := s.ListCharts(ChartListOptions{IncludeDeprecated: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("ListCharts() call %d = %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+func TestListChartEntries(t *testing.T) {
+	s := newRenderTestService(t,
+		&chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "app", Version: "1.0.0"}},
+		&chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "legacy", Version: "0.1.0", Deprecated: true}},
+	)
+	if err := os.WriteFile(filepath.Join(s.chartsDir, "broken.tgz"), []byte("not a chart"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts ChartListOptions
+		want []ChartEntry
+	}{
+		{"include deprecated", ChartListOptions{IncludeDeprecated: true}, []ChartEntry{
+			{Filename: "app-1.0.0.tgz", Name: "app", Version: "1.0.0"},
+			{Filename: "broken.tgz", Name: "broken.tgz"},
+			{Filename: "legacy-0.1.0.tgz", Name: "legacy", Version: "0.1.0", Deprecated: true},
+		}},
+		{"exclude deprecated", ChartListOptions{}, []ChartEntry{
+			{Filename: "app-1.0.0.tgz", Name: "app", Version: "1.0.0"},
+			{Filename: "broken.tgz", Name: "broken.tgz"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ListChartEntries(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListChartEntries() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"helm.sh/helm/v3/pkg/ignore"
+)
+
+// IgnoredFiles 返回 Chart 目录中被 .helmignore（及 helm 默认规则）排除、不会被打包的文件，
+// 规则与 helm 加载 Chart 目录时一致；被整体排除的目录以 "/" 结尾
+func (s *HelmService) IgnoredFiles(chartDir string) ([]string, error) {
+	topdir, err := filepath.Abs(chartDir)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := ignore.Empty()
+	ifile := filepath.Join(topdir, ignore.HelmIgnore)
+	if _, err := os.Stat(ifile); err == nil {
+		r, err := ignore.ParseFile(ifile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", ignore.HelmIgnore, err)
+		}
+		rules = r
+	}
+	rules.AddDefaults()
+
+	ignored := []string{}
+	err = filepath.WalkDir(topdir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		n, err := filepath.Rel(topdir, path)
+		if err != nil {
+			return err
+		}
+		// 顶层目录不参与匹配
+		if n == "." {
+			return nil
+		}
+		n = filepath.ToSlash(n)
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !rules.Ignore(n, fi) {
+			return nil
+		}
+		if d.IsDir() {
+			ignored = append(ignored, n+"/")
+			return filepath.SkipDir
+		}
+		ignored = append(ignored, n)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk chart directory: %w", err)
+	}
+
+	sort.Strings(ignored)
+	return ignored, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+func TestIgnoredFiles(t *testing.T) {
+	files := map[string]string{
+		"Chart.yaml":          "apiVersion: v2\nname: app\nversion: 0.1.0\n",
+		"values.yaml":         "replicas: 1\n",
+		"templates/cm.yaml":   "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n",
+		"templates/notes.bak": "backup",
+		"secrets.env":         "TOKEN=x",
+		"ci/values-ci.yaml":   "replicas: 2\n",
+		"docs/README.md":      "docs",
+	}
+
+	tests := []struct {
+		name       string
+		helmignore string
+		want       []string
+	}{
+		{"no helmignore", "", []string{}},
+		{"file pattern", "secrets.env\n", []string{"secrets.env"}},
+		{"glob pattern", "*.bak\n", []string{"templates/notes.bak"}},
+		{"directories", "ci/\ndocs/\n", []string{"ci/", "docs/"}},
+		{"comments and blank lines", "# local only\n\n*.env\n", []string{"secrets.env"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, data := range files {
+				writeTestFile(t, filepath.Join(dir, name), data)
+			}
+			if tt.helmignore != "" {
+				writeTestFile(t, filepath.Join(dir, ".helmignore"), tt.helmignore)
+			}
+
+			s := newRenderTestService(t)
+			ignored, err := s.IgnoredFiles(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(ignored, tt.want) {
+				t.Errorf("IgnoredFiles() = %v, want %v", ignored, tt.want)
+			}
+
+			// 被忽略的文件不会出现在打包后的 Chart 中
+			packaged, err := s.PackageChart(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := loader.Load(packaged)
+			if err != nil {
+				t.Fatal(err)
+			}
+			packagedFiles := map[string]bool{}
+			for _, f := range c.Raw {
+				packagedFiles[f.Name] = true
+			}
+			for file := range files {
+				excluded := false
+				for _, name := range tt.want {
+					if file == name || (strings.HasSuffix(name, "/") && strings.HasPrefix(file, name)) {
+						excluded = true
+					}
+				}
+				if packagedFiles[file] == excluded {
+					t.Errorf("%s packaged = %v, want %v", file, packagedFiles[file], !excluded)
+				}
+			}
+		})
+	}
+}
+
+// writeTestFile 写入文件并创建所需的目录
+func writeTestFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
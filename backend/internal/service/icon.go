@@ -0,0 +1,176 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// iconFetchTimeout 是拉取远程图标的超时时间
+	iconFetchTimeout = 10 * time.Second
+	// maxIconSize 是图标允许的最大字节数
+	maxIconSize = 1 << 20
+	// maxIconRedirects 是拉取图标时允许跟随的最大重定向次数
+	maxIconRedirects = 3
+)
+
+// iconClient 是拉取远程图标使用的客户端，只允许连接公网地址
+var iconClient = newIconClient(isPublicIP)
+
+// ChartIcon 表示 Chart 的图标内容
+type ChartIcon struct {
+	Data        []byte
+	ContentType string
+}
+
+// GetChartIcon 获取 Chart 元数据中 icon 指向的图片
+func (s *HelmService) GetChartIcon(name, version string) (*ChartIcon, error) {
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	icon := strings.TrimSpace(chart.Metadata.Icon)
+	if icon == "" {
+		return nil, ErrIconNotFound
+	}
+
+	if strings.HasPrefix(icon, "data:") {
+		return decodeDataIcon(icon)
+	}
+	return fetchIcon(iconClient, icon)
+}
+
+// newIconClient 创建拉取图标的客户端。图标地址来自上传的 Chart，为防止借此访问内网服务，
+// 连接在 DNS 解析之后按实际的目标 IP 由 allowed 校验，每次重定向都重新校验协议并经过同样的连接检查。
+// 客户端不使用代理，否则校验的只是代理的地址
+func newIconClient(allowed func(net.IP) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: iconFetchTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allowed(ip) {
+				return fmt.Errorf("icon host resolves to disallowed address %s", host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: iconFetchTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: iconFetchTimeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxIconRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxIconRedirects)
+			}
+			return checkIconURL(req.URL)
+		},
+	}
+}
+
+// isPublicIP 判断地址是否可以作为图标的来源，回环、私有、链路本地、组播与未指定地址均被拒绝
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// checkIconURL 校验图标地址只使用 http 或 https
+func checkIconURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported icon url: %s", u.Redacted())
+	}
+	return nil
+}
+
+// fetchIcon 使用 client 从远程地址拉取图标，并限制大小与内容类型
+func fetchIcon(client *http.Client, iconURL string) (*ChartIcon, error) {
+	u, err := url.Parse(iconURL)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported icon url: %s", iconURL)
+	}
+	if err := checkIconURL(u); err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch icon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch icon: unexpected status %s", resp.Status)
+	}
+
+	contentType, err := imageContentType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxIconSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read icon: %w", err)
+	}
+	if len(data) > maxIconSize {
+		return nil, fmt.Errorf("icon exceeds maximum size of %d bytes", maxIconSize)
+	}
+
+	return &ChartIcon{Data: data, ContentType: contentType}, nil
+}
+
+// decodeDataIcon 解析 data: URI 形式的图标
+func decodeDataIcon(icon string) (*ChartIcon, error) {
+	meta, payload, ok := strings.Cut(strings.TrimPrefix(icon, "data:"), ",")
+	if !ok {
+		return nil, fmt.Errorf("invalid data uri icon")
+	}
+
+	isBase64 := strings.HasSuffix(meta, ";base64")
+	contentType, err := imageContentType(strings.TrimSuffix(meta, ";base64"))
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if isBase64 {
+		data, err = base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode data uri icon: %w", err)
+		}
+	} else {
+		decoded, err := url.PathUnescape(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode data uri icon: %w", err)
+		}
+		data = []byte(decoded)
+	}
+
+	if len(data) > maxIconSize {
+		return nil, fmt.Errorf("icon exceeds maximum size of %d bytes", maxIconSize)
+	}
+
+	return &ChartIcon{Data: data, ContentType: contentType}, nil
+}
+
+// imageContentType 校验内容类型必须为 image/*
+func imageContentType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "image/") {
+		return "", fmt.Errorf("icon content type %q is not an image", contentType)
+	}
+	return mediaType, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1::1", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00:ec2::254", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := isPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+				t.Errorf("isPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchIcon(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// /redirect/N 重定向 N 次后返回图标
+		if n, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/redirect/")); err == nil && n > 0 {
+			http.Redirect(w, r, "/redirect/"+strconv.Itoa(n-1), http.StatusFound)
+			return
+		}
+		switch r.URL.Path {
+		case "/file":
+			http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+		case "/text":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+		default:
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(png)
+		}
+	}))
+	defer srv.Close()
+
+	allowAll := newIconClient(func(net.IP) bool { return true })
+
+	tests := []struct {
+		name    string
+		client  *http.Client
+		url     string
+		wantErr string
+	}{
+		{"allowed", allowAll, srv.URL + "/icon.png", ""},
+		{"loopback rejected after resolution", iconClient, srv.URL + "/icon.png", "disallowed address"},
+		{"localhost name rejected", iconClient, strings.Replace(srv.URL, "127.0.0.1", "localhost", 1) + "/icon.png", "disallowed address"},
+		{"redirects within limit", allowAll, srv.URL + "/redirect/3", ""},
+		{"too many redirects", allowAll, srv.URL + "/redirect/4", "stopped after 3 redirects"},
+		{"redirect to other scheme", allowAll, srv.URL + "/file", "unsupported icon url"},
+		{"unsupported scheme", allowAll, "ftp://example.com/icon.png", "unsupported icon url"},
+		{"not an image", allowAll, srv.URL + "/text", "is not an image"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			icon, err := fetchIcon(tt.client, tt.url)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(icon.Data) != string(png) || icon.ContentType != "image/png" {
+				t.Errorf("got %q (%s)", icon.Data, icon.ContentType)
+			}
+		})
+	}
+}
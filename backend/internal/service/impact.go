@@ -0,0 +1,193 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+// 资源变更类型
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// ResourceChange 描述单个资源在两次渲染之间的变化
+type ResourceChange struct {
+	Kind        string   `json:"kind"`
+	Name        string   `json:"name"`
+	Change      string   `json:"change"`
+	Disruptions []string `json:"disruptions,omitempty"`
+}
+
+// KindImpact 统计某一类资源的变化数量
+type KindImpact struct {
+	Added    int `json:"added"`
+	Removed  int `json:"removed"`
+	Modified int `json:"modified"`
+}
+
+// ImpactSummary 汇总升级对渲染结果的影响
+type ImpactSummary struct {
+	Added      int                   `json:"added"`
+	Removed    int                   `json:"removed"`
+	Modified   int                   `json:"modified"`
+	ByKind     map[string]KindImpact `json:"byKind"`
+	Changes    []ResourceChange      `json:"changes"`
+	Disruptive bool                  `json:"disruptive"`
+}
+
+// UpgradeImpact 使用相同的 values 渲染 Chart 的两个版本，按资源汇总新增、删除与修改，
+// 并标记可能造成中断的变更
+func (s *HelmService) UpgradeImpact(name, fromVersion, toVersion string, values map[string]interface{}, releaseName, namespace string) (*ImpactSummary, error) {
+	// hook 不属于 release 的常驻资源，不计入升级影响
+	from, err := s.RenderChart(name, fromVersion, values, releaseName, namespace, RenderOptions{NoHooks: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render version %s: %w", fromVersion, err)
+	}
+
+	to, err := s.RenderChart(name, toVersion, values, releaseName, namespace, RenderOptions{NoHooks: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render version %s: %w", toVersion, err)
+	}
+
+	return summarizeImpact(indexResources(from.Manifest), indexResources(to.Manifest)), nil
+}
+
+// resourceObject 是解析后的单个资源
+type resourceObject struct {
+	kind   string
+	name   string
+	object map[string]interface{}
+}
+
+// indexResources 按 kind/name 索引渲染结果中的资源
+func indexResources(manifest string) map[string]resourceObject {
+	resources := map[string]resourceObject{}
+	for _, m := range splitManifests(manifest) {
+		head := parseManifestHead(m)
+		if head.Kind == "" || head.Metadata.Name == "" {
+			continue
+		}
+
+		object := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(m), &object); err != nil {
+			continue
+		}
+
+		resources[head.Kind+"/"+head.Metadata.Name] = resourceObject{
+			kind:   head.Kind,
+			name:   head.Metadata.Name,
+			object: object,
+		}
+	}
+	return resources
+}
+
+// summarizeImpact 对比两组资源并生成影响汇总
+func summarizeImpact(from, to map[string]resourceObject) *ImpactSummary {
+	summary := &ImpactSummary{ByKind: map[string]KindImpact{}, Changes: []ResourceChange{}}
+
+	record := func(res resourceObject, change string, disruptions []string) {
+		impact := summary.ByKind[res.kind]
+		switch change {
+		case ChangeAdded:
+			summary.Added++
+			impact.Added++
+		case ChangeRemoved:
+			summary.Removed++
+			impact.Removed++
+		case ChangeModified:
+			summary.Modified++
+			impact.Modified++
+		}
+		summary.ByKind[res.kind] = impact
+
+		if len(disruptions) > 0 {
+			summary.Disruptive = true
+		}
+		summary.Changes = append(summary.Changes, ResourceChange{
+			Kind:        res.kind,
+			Name:        res.name,
+			Change:      change,
+			Disruptions: disruptions,
+		})
+	}
+
+	for key, old := range from {
+		updated, ok := to[key]
+		switch {
+		case !ok:
+			record(old, ChangeRemoved, removalDisruptions(old))
+		case !reflect.DeepEqual(old.object, updated.object):
+			record(updated, ChangeModified, modificationDisruptions(old, updated))
+		}
+	}
+	for key, res := range to {
+		if _, ok := from[key]; !ok {
+			record(res, ChangeAdded, nil)
+		}
+	}
+
+	sort.Slice(summary.Changes, func(i, j int) bool {
+		a, b := summary.Changes[i], summary.Changes[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+
+	return summary
+}
+
+// removalDisruptions 返回删除资源可能造成的中断
+func removalDisruptions(res resourceObject) []string {
+	switch res.kind {
+	case "PersistentVolumeClaim":
+		return []string{"removing a PersistentVolumeClaim may delete its data"}
+	case "CustomResourceDefinition":
+		return []string{"removing a CustomResourceDefinition deletes all of its custom resources"}
+	}
+	return nil
+}
+
+// modificationDisruptions 返回修改资源可能造成的中断
+func modificationDisruptions(old, updated resourceObject) []string {
+	var disruptions []string
+
+	switch old.kind {
+	case "Service":
+		oldType, newType := nestedString(old.object, "spec", "type"), nestedString(updated.object, "spec", "type")
+		if oldType != newType {
+			disruptions = append(disruptions, fmt.Sprintf("service type changes from %q to %q", oldType, newType))
+		}
+	case "Deployment", "StatefulSet", "DaemonSet":
+		if !reflect.DeepEqual(nestedValue(old.object, "spec", "selector"), nestedValue(updated.object, "spec", "selector")) {
+			disruptions = append(disruptions, "spec.selector is immutable and changing it fails the upgrade")
+		}
+	}
+
+	return disruptions
+}
+
+// nestedValue 按路径读取嵌套 map 中的值
+func nestedValue(object map[string]interface{}, fields ...string) interface{} {
+	var current interface{} = object
+	for _, field := range fields {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[field]
+	}
+	return current
+}
+
+// nestedString 按路径读取嵌套 map 中的字符串
+func nestedString(object map[string]interface{}, fields ...string) string {
+	v, _ := nestedValue(object, fields...).(string)
+	return v
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"runtime/debug"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// ServerInfo 描述服务端使用的 Helm 版本与默认渲染能力
+type ServerInfo struct {
+	HelmVersion      string `json:"helmVersion"`
+	KubeVersion      string `json:"kubeVersion"`
+	ClusterReachable bool   `json:"clusterReachable"`
+	ChartsDir        string `json:"chartsDir,omitempty"`
+	TempDir          string `json:"tempDir,omitempty"`
+}
+
+// Info 返回服务端的 Helm 版本、默认 Kubernetes 版本及集群连通性
+func (s *HelmService) Info() ServerInfo {
+	info := ServerInfo{
+		HelmVersion: helmVersion(),
+		KubeVersion: chartutil.DefaultCapabilities.KubeVersion.Version,
+	}
+
+	_, err := s.kubeClient()
+	info.ClusterReachable = err == nil
+
+	// 目录路径仅在调试模式下返回
+	if s.debug {
+		info.ChartsDir = s.chartsDir
+		info.TempDir = s.tempDir
+	}
+
+	return info
+}
+
+// helmVersion 从构建信息中读取 Helm SDK 的版本
+func helmVersion() string {
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	for _, dep := range buildInfo.Deps {
+		if dep.Path == "helm.sh/helm/v3" {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"sigs.k8s.io/yaml"
+)
+
+// releaseInstallTimeout 是安装 release 的超时时间
+const releaseInstallTimeout = 5 * time.Minute
+
+// InstallResult 描述安装完成后 release 的状态
+type InstallResult struct {
+	Name         string    `json:"name"`
+	Namespace    string    `json:"namespace"`
+	Revision     int       `json:"revision"`
+	Status       string    `json:"status"`
+	Chart        string    `json:"chart"`
+	ChartVersion string    `json:"chartVersion"`
+	Updated      time.Time `json:"updated"`
+	Notes        string    `json:"notes,omitempty"`
+	// RolledBack 表示 atomic 安装失败后 release 已被自动卸载，RollbackReason 为失败原因
+	RolledBack     bool   `json:"rolledBack,omitempty"`
+	RollbackReason string `json:"rollbackReason,omitempty"`
+}
+
+// InstallOptions 定义安装 release 的可选行为
+type InstallOptions struct {
+	// Atomic 为 true 时等待资源就绪，失败则自动卸载 release
+	Atomic bool
+	// NoHooks 为 true 时不执行 Chart 的 hook，与 helm install --no-hooks 一致
+	NoHooks bool
+}
+
+// ParseValuesFile 解析上传的 values YAML，内容不合法时返回 ErrInvalidValues
+func ParseValuesFile(data []byte, filename string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidValues, filename, err)
+	}
+	return values, nil
+}
+
+// InstallChart 将 Chart 安装到集群中，values 的处理方式与渲染一致；ctx 取消时 helm 停止等待并返回错误。
+// opts.Atomic 为 true 时安装失败会自动卸载 release，此时返回的结果中 RolledBack 为 true 而不返回错误
+func (s *HelmService) InstallChart(ctx context.Context, name, version string, values map[string]interface{}, releaseName, namespace string, opts InstallOptions) (*InstallResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := s.kubeClient(); err != nil {
+		return nil, err
+	}
+
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDependencies(chart); err != nil {
+		return nil, err
+	}
+
+	actionConfig, err := s.newActionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	client := action.NewInstall(actionConfig)
+	client.ReleaseName = releaseName
+	client.Namespace = namespace
+	client.Timeout = releaseInstallTimeout
+	client.Atomic = opts.Atomic
+	client.Wait = opts.Atomic
+	client.DisableHooks = opts.NoHooks
+
+	values, err = s.prepareValues(chart.Metadata.Name, namespace, values)
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := client.RunWithContext(ctx, chart, values)
+	if err != nil {
+		// helm 在卸载成功后才会移除 release 记录，卸载失败时仍按安装失败处理
+		if !opts.Atomic || rel == nil || !releaseRemoved(actionConfig, releaseName) {
+			return nil, fmt.Errorf("failed to install release: %w", err)
+		}
+	}
+
+	result := &InstallResult{
+		Name:         rel.Name,
+		Namespace:    rel.Namespace,
+		Revision:     rel.Version,
+		Chart:        chart.Metadata.Name,
+		ChartVersion: chart.Metadata.Version,
+	}
+	if rel.Info != nil {
+		result.Status = rel.Info.Status.String()
+		result.Updated = rel.Info.LastDeployed.Time
+		result.Notes = rel.Info.Notes
+	}
+	if err != nil {
+		result.RolledBack = true
+		result.RollbackReason = err.Error()
+	}
+	return result, nil
+}
+
+// releaseRemoved 判断 release 是否已不存在于集群中
+func releaseRemoved(actionConfig *action.Configuration, releaseName string) bool {
+	_, err := actionConfig.Releases.Last(releaseName)
+	return errors.Is(err, driver.ErrReleaseNotFound)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// renderLimiter 限制同时执行的渲染数量，并对排队的请求数量设置上限
+type renderLimiter struct {
+	slots     chan struct{}
+	maxQueued int64
+	inFlight  atomic.Int64
+	queued    atomic.Int64
+}
+
+// newRenderLimiter 创建渲染并发限制器
+func newRenderLimiter(maxConcurrent, maxQueued int) *renderLimiter {
+	return &renderLimiter{
+		slots:     make(chan struct{}, maxConcurrent),
+		maxQueued: int64(maxQueued),
+	}
+}
+
+// acquire 获取一个渲染槽位，排队已满时返回 ErrRenderQueueFull；
+// 排队期间 ctx 取消或超时时放弃排队并返回 ctx 的错误
+func (l *renderLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		l.inFlight.Add(1)
+		return nil
+	default:
+	}
+
+	if l.queued.Add(1) > l.maxQueued {
+		l.queued.Add(-1)
+		return ErrRenderQueueFull
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		l.queued.Add(-1)
+		l.inFlight.Add(1)
+		return nil
+	case <-ctx.Done():
+		l.queued.Add(-1)
+		return ctx.Err()
+	}
+}
+
+// release 释放渲染槽位
+func (l *renderLimiter) release() {
+	l.inFlight.Add(-1)
+	<-l.slots
+}
+
+// RenderStats 返回当前正在执行与排队中的渲染数量
+func (s *HelmService) RenderStats() (inFlight, queued int64) {
+	return s.renderLimiter.inFlight.Load(), s.renderLimiter.queued.Load()
+}
+
+// envInt 读取正整数类型的环境变量，未设置或非法时返回默认值
+func envInt(key string, def int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return def
+}
+
+// defaultMaxConcurrentRenders 返回默认的最大并发渲染数
+func defaultMaxConcurrentRenders() int {
+	return runtime.NumCPU()
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// waitQueued 等待排队数量达到 n
+func waitQueued(t *testing.T, l *renderLimiter, n int64) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for l.queued.Load() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("queued = %d, want %d", l.queued.Load(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRenderLimiterQueueFull(t *testing.T) {
+	l := newRenderLimiter(1, 1)
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// 第二个请求排队等待
+	done := make(chan error, 1)
+	go func() { done <- l.acquire(context.Background()) }()
+	waitQueued(t, l, 1)
+
+	// 排队已满，第三个请求立即被拒绝
+	if err := l.acquire(context.Background()); !errors.Is(err, ErrRenderQueueFull) {
+		t.Fatalf("acquire() error = %v, want ErrRenderQueueFull", err)
+	}
+	if got := l.queued.Load(); got != 1 {
+		t.Errorf("queued = %d after rejection, want 1", got)
+	}
+
+	l.release()
+	if err := <-done; err != nil {
+		t.Fatalf("queued acquire() error = %v", err)
+	}
+	if inFlight, queued := l.inFlight.Load(), l.queued.Load(); inFlight != 1 || queued != 0 {
+		t.Errorf("inFlight = %d, queued = %d, want 1 and 0", inFlight, queued)
+	}
+}
+
+func TestRenderLimiterContextWhileQueued(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		cancel  bool
+		wantErr error
+	}{
+		{
+			name:    "canceled",
+			ctx:     func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			cancel:  true,
+			wantErr: context.Canceled,
+		},
+		{
+			name: "deadline exceeded",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 100*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRenderLimiter(1, 1)
+			if err := l.acquire(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			done := make(chan error, 1)
+			go func() { done <- l.acquire(ctx) }()
+			waitQueued(t, l, 1)
+			if tt.cancel {
+				cancel()
+			}
+
+			select {
+			case err := <-done:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("acquire() error = %v, want %v", err, tt.wantErr)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("queued acquire() did not return after its context ended")
+			}
+
+			// 放弃排队的请求不占用排队名额，也不会在之后拿走渲染槽位
+			if inFlight, queued := l.inFlight.Load(), l.queued.Load(); inFlight != 1 || queued != 0 {
+				t.Errorf("inFlight = %d, queued = %d, want 1 and 0", inFlight, queued)
+			}
+			l.release()
+			if err := l.acquire(context.Background()); err != nil {
+				t.Fatalf("acquire() after release error = %v", err)
+			}
+		})
+	}
+}
+
+func TestRenderLimiterCanceledContextWithFreeSlot(t *testing.T) {
+	// 有空闲槽位时直接获取，与 ctx 状态无关
+	l := newRenderLimiter(1, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.acquire(ctx); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+}
+
+func TestRenderLimiterBoundsConcurrency(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxConcurrent int
+		maxQueued     int
+		requests      int
+	}{
+		{"all requests fit in the queue", 2, 20, 20},
+		{"excess requests rejected", 2, 3, 20},
+		{"single slot", 1, 5, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRenderLimiter(tt.maxConcurrent, tt.maxQueued)
+
+			var running, peak, rejected atomic.Int64
+			var wg sync.WaitGroup
+			for i := 0; i < tt.requests; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := l.acquire(context.Background()); err != nil {
+						if !errors.Is(err, ErrRenderQueueFull) {
+							t.Errorf("acquire() error = %v", err)
+						}
+						rejected.Add(1)
+						return
+					}
+					n := running.Add(1)
+					for {
+						p := peak.Load()
+						if n <= p || peak.CompareAndSwap(p, n) {
+							break
+						}
+					}
+					time.Sleep(5 * time.Millisecond)
+					running.Add(-1)
+					l.release()
+				}()
+			}
+			wg.Wait()
+
+			if got := peak.Load(); got > int64(tt.maxConcurrent) {
+				t.Errorf("peak concurrency = %d, want at most %d", got, tt.maxConcurrent)
+			}
+			// 同时到达的请求中，最多 maxConcurrent 个执行、maxQueued 个排队，其余被拒绝
+			maxRejected := tt.requests - tt.maxConcurrent - tt.maxQueued
+			if maxRejected < 0 {
+				maxRejected = 0
+			}
+			if got := rejected.Load(); got > int64(maxRejected) {
+				t.Errorf("rejected = %d, want at most %d", got, maxRejected)
+			}
+			if inFlight, queued := l.inFlight.Load(), l.queued.Load(); inFlight != 0 || queued != 0 {
+				t.Errorf("inFlight = %d, queued = %d after all requests finished, want 0 and 0", inFlight, queued)
+			}
+		})
+	}
+}
+
+func TestEnvInt(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{"unset", "", 4},
+		{"positive", "8", 8},
+		{"zero", "0", 4},
+		{"negative", "-1", 4},
+		{"not a number", "many", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HELM_UI_TEST_INT", tt.value)
+			if got := envInt("HELM_UI_TEST_INT", 4); got != tt.want {
+				t.Errorf("envInt() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderChartBoundedMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("load test")
+	}
+
+	// 每次渲染输出约 1MB，渲染过程中的中间数据是输出的数倍
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "big", Version: "0.1.0"},
+		Templates: []*chart.File{{
+			Name: "templates/cm.yaml",
+			Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: big\ndata:\n  payload: |\n{{- range until 16000 }}\n    line-{{ . }}-0123456789abcdefghijklmnopqrstuvwxyz0123456789\n{{- end }}\n"),
+		}},
+	}
+	s := newRenderTestService(t, c)
+	const renders = 32
+
+	// peakHeapGrowth 并发执行 renders 次渲染，返回期间堆内存相对开始时的最大增长
+	peakHeapGrowth := func(maxConcurrent int) uint64 {
+		s.renderLimiter = newRenderLimiter(maxConcurrent, renders)
+		runtime.GC()
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		base := stats.HeapInuse
+
+		var peak atomic.Uint64
+		done := make(chan struct{})
+		sampled := make(chan struct{})
+		go func() {
+			defer close(sampled)
+			var stats runtime.MemStats
+			for {
+				runtime.ReadMemStats(&stats)
+				if stats.HeapInuse > base && stats.HeapInuse-base > peak.Load() {
+					peak.Store(stats.HeapInuse - base)
+				}
+				select {
+				case <-done:
+					return
+				case <-time.After(time.Millisecond):
+				}
+			}
+		}()
+
+		var wg sync.WaitGroup
+		for i := 0; i < renders; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := s.RenderChart("big", "0.1.0", nil, "r", "default", RenderOptions{}); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+		close(done)
+		<-sampled
+		return peak.Load()
+	}
+
+	unbounded := peakHeapGrowth(renders)
+	bounded := peakHeapGrowth(2)
+	t.Logf("peak heap growth: %d MB with %d concurrent renders, %d MB with 2", unbounded>>20, renders, bounded>>20)
+
+	// 同时进行的渲染只有 2 个，堆内存的峰值应远低于不限制并发时
+	if bounded*3 > unbounded {
+		t.Errorf("peak heap growth with 2 concurrent renders = %d bytes, want well below %d bytes", bounded, unbounded)
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/lint/support"
+)
+
+// lintSeverities 对应 support 包中各级别的名称
+var lintSeverities = []string{"UNKNOWN", "INFO", "WARNING", "ERROR"}
+
+// LintMessage 描述一条 lint 结果
+type LintMessage struct {
+	Severity string `json:"severity"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
+
+// LintReport 汇总 Chart 的 lint 结果
+type LintReport struct {
+	Messages  []LintMessage `json:"messages"`
+	HasErrors bool          `json:"hasErrors"`
+}
+
+// LintChart 对 Chart 目录执行 lint 检查
+func (s *HelmService) LintChart(chartDir string) LintReport {
+	return lintChartDir(chartDir, nil)
+}
+
+// lintChartDir 使用给定的 values 对 Chart 目录执行 lint 检查
+func lintChartDir(chartDir string, values map[string]interface{}) LintReport {
+	result := action.NewLint().Run([]string{chartDir}, values)
+
+	report := LintReport{Messages: []LintMessage{}}
+	for _, msg := range result.Messages {
+		severity := "UNKNOWN"
+		if msg.Severity >= 0 && msg.Severity < len(lintSeverities) {
+			severity = lintSeverities[msg.Severity]
+		}
+
+		report.Messages = append(report.Messages, LintMessage{
+			Severity: severity,
+			Path:     msg.Path,
+			Message:  msg.Err.Error(),
+		})
+
+		if msg.Severity == support.ErrorSev {
+			report.HasErrors = true
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/yaml"
+)
+
+// manifestHead 是解析单个 manifest 时关心的字段
+type manifestHead struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name        string            `json:"name"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+}
+
+// splitManifests 按原始顺序拆分渲染结果中的 manifest
+func splitManifests(manifest string) []string {
+	manifests := releaseutil.SplitManifests(manifest)
+
+	keys := make([]string, 0, len(manifests))
+	for k := range manifests {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	result := make([]string, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, manifests[k])
+	}
+	return result
+}
+
+// parseManifestHead 解析 manifest 的 kind 与 metadata，解析失败时返回空结构
+func parseManifestHead(manifest string) manifestHead {
+	var head manifestHead
+	_ = yaml.Unmarshal([]byte(manifest), &head)
+	return head
+}
+
+// filterManifests 按文件与资源过滤渲染结果，多个过滤条件之间为“与”关系
+func filterManifests(manifest, chartName string, selectedFiles, resources []string) string {
+	if len(selectedFiles) == 0 && len(resources) == 0 {
+		return manifest
+	}
+
+	var filteredManifests []string
+	for _, m := range splitManifests(manifest) {
+		if len(selectedFiles) > 0 && !matchesFiles(m, chartName, selectedFiles) {
+			continue
+		}
+		if len(resources) > 0 && !matchesResources(m, resources) {
+			continue
+		}
+		filteredManifests = append(filteredManifests, m)
+	}
+
+	return strings.Join(filteredManifests, "\n---\n")
+}
+
+// matchesFiles 判断 manifest 是否来自指定的模板文件
+func matchesFiles(manifest, chartName string, selectedFiles []string) bool {
+	for _, selectedFile := range selectedFiles {
+		// 构建完整的文件路径模式
+		fullPath := fmt.Sprintf("%s/%s", chartName, selectedFile)
+		if strings.Contains(manifest, fmt.Sprintf("# Source: %s", fullPath)) {
+			return true
+		}
+	}
+	return false
+}
+
+// unmatchedFiles 返回在渲染结果中没有产生任何 manifest 的选中文件
+func unmatchedFiles(manifest, chartName string, selectedFiles []string) []string {
+	var unmatched []string
+	for _, selectedFile := range selectedFiles {
+		if !matchesFiles(manifest, chartName, []string{selectedFile}) {
+			unmatched = append(unmatched, selectedFile)
+		}
+	}
+	return unmatched
+}
+
+// matchesResources 判断 manifest 是否匹配 kind/name 形式的资源选择器
+func matchesResources(manifest string, resources []string) bool {
+	head := parseManifestHead(manifest)
+	if head.Kind == "" || head.Metadata.Name == "" {
+		return false
+	}
+
+	id := head.Kind + "/" + head.Metadata.Name
+	for _, resource := range resources {
+		if resource == id {
+			return true
+		}
+	}
+	return false
+}
+
+// 支持的 manifest 排序方式
+const (
+	// SortOrderNone 保持渲染输出的原始顺序
+	SortOrderNone = "none"
+	// SortOrderInstall 按 helm 的安装顺序（Namespace、CRD 等优先）排序
+	SortOrderInstall = "install"
+)
+
+// ValidSortOrder 判断 manifest 排序方式是否受支持，空字符串视为 none
+func ValidSortOrder(order string) bool {
+	return order == "" || order == SortOrderNone || order == SortOrderInstall
+}
+
+// sortManifestsByKind 按 helm 的 InstallOrder 对 manifest 稳定排序，未知的 kind 排在最后并按名称排序，
+// 与 helm 内部的 sortManifestsByKind 一致
+func sortManifestsByKind(manifest string) string {
+	ordering := make(map[string]int, len(releaseutil.InstallOrder))
+	for i, kind := range releaseutil.InstallOrder {
+		ordering[kind] = i
+	}
+
+	manifests := splitManifests(manifest)
+	kinds := make([]string, len(manifests))
+	for i, m := range manifests {
+		kinds[i] = parseManifestHead(m).Kind
+	}
+
+	indexes := make([]int, len(manifests))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		kindA, kindB := kinds[indexes[i]], kinds[indexes[j]]
+		first, aok := ordering[kindA]
+		second, bok := ordering[kindB]
+		switch {
+		case !aok && !bok:
+			return kindA < kindB
+		case !aok:
+			return false
+		case !bok:
+			return true
+		}
+		return first < second
+	})
+
+	sorted := make([]string, 0, len(manifests))
+	for _, i := range indexes {
+		sorted = append(sorted, manifests[i])
+	}
+	return strings.Join(sorted, "\n---\n")
+}
+
+// sortManifests 按 order 指定的方式排序 manifest
+func sortManifests(manifest, order string) string {
+	if order == SortOrderInstall {
+		return sortManifestsByKind(manifest)
+	}
+	return manifest
+}
+
+// SourceManifest 是同一模板文件产生的所有文档
+type SourceManifest struct {
+	// Path 为模板的完整路径（如 mychart/templates/deployment.yaml）
+	Path    string
+	Content string
+}
+
+// GroupManifestsBySource 按 "# Source:" 注释将渲染结果按模板文件分组，保持首次出现的顺序，
+// 只包含注释或空白的文档被忽略
+func GroupManifestsBySource(manifest string) []SourceManifest {
+	var groups []SourceManifest
+	index := map[string]int{}
+	for _, m := range splitManifests(manifest) {
+		if isEmptyManifest(m) {
+			continue
+		}
+		source := path.Clean("/" + manifestSource(m))[1:]
+		if source == "" {
+			source = "unknown"
+		}
+
+		i, ok := index[source]
+		if !ok {
+			index[source] = len(groups)
+			groups = append(groups, SourceManifest{Path: source, Content: m + "\n"})
+			continue
+		}
+		groups[i].Content += "---\n" + m + "\n"
+	}
+	return groups
+}
+
+// InvalidDocument 描述渲染结果中无法解析为 YAML 的文档
+type InvalidDocument struct {
+	// Index 为文档在渲染结果中的序号，从 0 开始
+	Index      int    `json:"index"`
+	SourceFile string `json:"sourceFile"`
+	Error      string `json:"error"`
+}
+
+// ValidateManifestYAML 逐个解析渲染结果中的文档，返回无法解析的文档，
+// 用于发现模板空白或缩进错误导致的非法 YAML
+func ValidateManifestYAML(manifest string) []InvalidDocument {
+	var invalid []InvalidDocument
+	for i, m := range splitManifests(manifest) {
+		var doc interface{}
+		if err := yaml.Unmarshal([]byte(m), &doc); err != nil {
+			invalid = append(invalid, InvalidDocument{Index: i, SourceFile: manifestSource(m), Error: err.Error()})
+		}
+	}
+	return invalid
+}
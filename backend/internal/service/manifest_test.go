@@ -0,0 +1,156 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// multiDocManifest 模拟 helm 的渲染输出：templates/app.yaml 产生 Deployment 与 Service 两个文档
+const multiDocManifest = `---
+# Source: demo/templates/app.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+# Source: demo/templates/app.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+# Source: demo/templates/cm.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`
+
+func TestFilterManifests(t *testing.T) {
+	tests := []struct {
+		name          string
+		selectedFiles []string
+		resources     []string
+		want          []string
+	}{
+		{
+			name: "no filter keeps everything",
+			want: []string{"Deployment/web", "Service/web", "ConfigMap/settings"},
+		},
+		{
+			name:          "file selects every document it emits",
+			selectedFiles: []string{"templates/app.yaml"},
+			want:          []string{"Deployment/web", "Service/web"},
+		},
+		{
+			name:      "kind/name selects one document of a multi-document file",
+			resources: []string{"Service/web"},
+			want:      []string{"Service/web"},
+		},
+		{
+			name:      "several resources across files",
+			resources: []string{"Deployment/web", "ConfigMap/settings"},
+			want:      []string{"Deployment/web", "ConfigMap/settings"},
+		},
+		{
+			name:          "file and resource filters are combined",
+			selectedFiles: []string{"templates/app.yaml"},
+			resources:     []string{"Deployment/web", "ConfigMap/settings"},
+			want:          []string{"Deployment/web"},
+		},
+		{
+			name:      "name must match exactly",
+			resources: []string{"Service/we"},
+			want:      nil,
+		},
+		{
+			name:          "unknown file",
+			selectedFiles: []string{"templates/missing.yaml"},
+			want:          nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := filterManifests(multiDocManifest, "demo", tt.selectedFiles, tt.resources)
+
+			var got []string
+			for _, m := range splitManifests(out) {
+				head := parseManifestHead(m)
+				if head.Kind == "" {
+					continue
+				}
+				got = append(got, head.Kind+"/"+head.Metadata.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterManifests() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmatchedFiles(t *testing.T) {
+	got := unmatchedFiles(multiDocManifest, "demo", []string{"templates/app.yaml", "templates/missing.yaml"})
+	want := []string{"templates/missing.yaml"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unmatchedFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestSortManifests(t *testing.T) {
+	doc := func(kind, name string) string {
+		return "apiVersion: v1\nkind: " + kind + "\nmetadata:\n  name: " + name
+	}
+	manifest := strings.Join([]string{
+		doc("Deployment", "web"),
+		doc("Widget", "custom"),
+		doc("Service", "web"),
+		doc("Namespace", "apps"),
+		doc("Alpha", "custom"),
+		doc("CustomResourceDefinition", "widgets"),
+		doc("Deployment", "worker"),
+	}, "\n---\n")
+
+	tests := []struct {
+		name  string
+		order string
+		want  []string
+	}{
+		{"none keeps order", SortOrderNone, []string{"Deployment/web", "Widget/custom", "Service/web", "Namespace/apps", "Alpha/custom", "CustomResourceDefinition/widgets", "Deployment/worker"}},
+		{"empty keeps order", "", []string{"Deployment/web", "Widget/custom", "Service/web", "Namespace/apps", "Alpha/custom", "CustomResourceDefinition/widgets", "Deployment/worker"}},
+		// 同 kind 保持原顺序，未知 kind 排在最后并按名称排序
+		{"install order", SortOrderInstall, []string{"Namespace/apps", "CustomResourceDefinition/widgets", "Service/web", "Deployment/web", "Deployment/worker", "Alpha/custom", "Widget/custom"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, m := range splitManifests(sortManifests(manifest, tt.order)) {
+				head := parseManifestHead(m)
+				got = append(got, head.Kind+"/"+head.Metadata.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortManifests() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidSortOrder(t *testing.T) {
+	tests := []struct {
+		order string
+		want  bool
+	}{
+		{"", true},
+		{SortOrderNone, true},
+		{SortOrderInstall, true},
+		{"uninstall", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidSortOrder(tt.order); got != tt.want {
+			t.Errorf("ValidSortOrder(%q) = %v, want %v", tt.order, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/engine"
+)
+
+// RenderNotes 仅渲染 Chart 的 NOTES.txt，Chart 不包含 NOTES.txt 时返回空字符串
+func (s *HelmService) RenderNotes(name, version string, values map[string]interface{}, releaseName, namespace string) (string, error) {
+	if err := s.renderLimiter.acquire(context.Background()); err != nil {
+		return "", err
+	}
+	defer s.renderLimiter.release()
+
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return "", err
+	}
+
+	var notesTemplate string
+	for _, tpl := range chart.Templates {
+		if strings.EqualFold(path.Base(tpl.Name), "NOTES.txt") && path.Dir(tpl.Name) == "templates" {
+			notesTemplate = path.Join(chart.ChartFullPath(), tpl.Name)
+			break
+		}
+	}
+	if notesTemplate == "" {
+		return "", nil
+	}
+
+	valuesToRender, err := s.renderValues(chart, values, releaseName, namespace, RenderOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	files, err := engine.Render(isolateTemplate(chart, notesTemplate), valuesToRender)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrRenderFailed, err)
+	}
+
+	return strings.TrimSpace(files[notesTemplate]), nil
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// loadNamespaceDefaults 从 YAML 文件加载命名空间到默认 values 的映射
+func loadNamespaceDefaults(path string) (map[string]map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read namespace defaults: %w", err)
+	}
+
+	defaults := map[string]map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &defaults); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace defaults: %w", err)
+	}
+
+	return defaults, nil
+}
+
+// NamespaceDefaults 返回指定命名空间生效的默认 values，未配置时返回空 map
+func (s *HelmService) NamespaceDefaults(namespace string) map[string]interface{} {
+	if defaults, ok := s.namespaceDefaults[namespace]; ok {
+		return defaults
+	}
+	return map[string]interface{}{}
+}
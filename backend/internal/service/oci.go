@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/registry"
+)
+
+// OCIPullOptions 定义从 OCI 仓库拉取 Chart 的可选参数
+type OCIPullOptions struct {
+	// Username 与 Password 均为空时使用 HELM_UI_REGISTRY_CONFIG 指定的凭据文件，未配置时匿名拉取
+	Username string
+	Password string
+	// PlainHTTP 为 true 时通过 HTTP 访问仓库
+	PlainHTTP bool
+}
+
+// PullChartFromOCI 从 OCI 仓库拉取 Chart 并按普通上传保存，返回 Chart 的名称与版本。
+// 提供用户名密码时登录信息只写入临时凭据文件，拉取结束后即删除
+func (s *HelmService) PullChartFromOCI(ref string, opts OCIPullOptions) (string, string, error) {
+	if !registry.IsOCI(ref) {
+		return "", "", fmt.Errorf("%w: %s is not an oci:// reference", ErrInvalidChart, ref)
+	}
+	ref = strings.TrimPrefix(ref, fmt.Sprintf("%s://", registry.OCIScheme))
+	host, _, _ := strings.Cut(ref, "/")
+
+	credentialsFile := os.Getenv("HELM_UI_REGISTRY_CONFIG")
+	if opts.Username != "" || opts.Password != "" || credentialsFile == "" {
+		tmp, err := s.tempCredentialsFile()
+		if err != nil {
+			return "", "", err
+		}
+		defer os.Remove(tmp)
+		credentialsFile = tmp
+	}
+
+	clientOpts := []registry.ClientOption{
+		registry.ClientOptWriter(io.Discard),
+		registry.ClientOptCredentialsFile(credentialsFile),
+	}
+	if opts.PlainHTTP {
+		clientOpts = append(clientOpts, registry.ClientOptPlainHTTP())
+	}
+	client, err := registry.NewClient(clientOpts...)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create registry client: %w", err)
+	}
+
+	if opts.Username != "" || opts.Password != "" {
+		err := client.Login(host,
+			registry.LoginOptBasicAuth(opts.Username, opts.Password),
+			registry.LoginOptInsecure(opts.PlainHTTP))
+		if err != nil {
+			return "", "", registryError(fmt.Sprintf("failed to log in to %s", host), err)
+		}
+	}
+
+	result, err := client.Pull(ref)
+	if err != nil {
+		return "", "", registryError(fmt.Sprintf("failed to pull %s", ref), err)
+	}
+
+	return s.UploadChartArchive(result.Chart.Data)
+}
+
+// tempCredentialsFile 创建只在本次拉取中使用的空凭据文件
+func (s *HelmService) tempCredentialsFile() (string, error) {
+	if err := os.MkdirAll(s.tempDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	f, err := os.CreateTemp(s.tempDir, "registry-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create registry credentials: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString("{}"); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to create registry credentials: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// registryAuthErrorPattern 匹配认证失败的状态码与提示；状态码须为独立的数字，
+// 避免错误信息中的端口号（如 127.0.0.1:40141）被误判
+var registryAuthErrorPattern = regexp.MustCompile(`\b40[13]\b|unauthorized|denied`)
+
+// registryError 将认证失败的错误包装为 ErrRegistryUnauthorized
+func registryError(msg string, err error) error {
+	if registryAuthErrorPattern.MatchString(strings.ToLower(err.Error())) {
+		return fmt.Errorf("%w: %s: %v", ErrRegistryUnauthorized, msg, err)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// basicAuthRegistry 启动一个只实现认证的 OCI 仓库桩：凭据错误时返回 401，
+// 凭据正确时 /v2/ 返回 200，其余请求返回 404
+func basicAuthRegistry(t *testing.T, username, password string) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != username || p != password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			http.Error(w, `{"errors":[{"code":"UNAUTHORIZED","message":"authentication required"}]}`, http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/v2/" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.Error(w, `{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`, http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+func TestPullChartFromOCIAuth(t *testing.T) {
+	host := basicAuthRegistry(t, "user", "secret")
+	ref := "oci://" + host + "/charts/app:0.1.0"
+
+	tests := []struct {
+		name             string
+		ref              string
+		opts             OCIPullOptions
+		wantUnauthorized bool
+	}{
+		{"anonymous pull of private chart", ref, OCIPullOptions{PlainHTTP: true}, true},
+		{"wrong password", ref, OCIPullOptions{Username: "user", Password: "wrong", PlainHTTP: true}, true},
+		// 登录成功后拉取不存在的 Chart，错误不应被当作认证失败
+		{"valid credentials", ref, OCIPullOptions{Username: "user", Password: "secret", PlainHTTP: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HELM_UI_REGISTRY_CONFIG", "")
+			s := newRenderTestService(t)
+
+			_, _, err := s.PullChartFromOCI(tt.ref, tt.opts)
+			if err == nil {
+				t.Fatal("PullChartFromOCI() succeeded, want an error from the stub registry")
+			}
+			if got := errors.Is(err, ErrRegistryUnauthorized); got != tt.wantUnauthorized {
+				t.Errorf("errors.Is(%v, ErrRegistryUnauthorized) = %v, want %v", err, got, tt.wantUnauthorized)
+			}
+
+			// 临时凭据文件在拉取结束后删除
+			entries, err := os.ReadDir(s.tempDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("temp directory is not empty: %v", entries)
+			}
+		})
+	}
+}
+
+func TestPullChartFromOCIInvalidRef(t *testing.T) {
+	s := newRenderTestService(t)
+	if _, _, err := s.PullChartFromOCI("https://example.com/charts/app", OCIPullOptions{}); !errors.Is(err, ErrInvalidChart) {
+		t.Errorf("PullChartFromOCI() error = %v, want %v", err, ErrInvalidChart)
+	}
+}
+
+func TestRegistryError(t *testing.T) {
+	tests := []struct {
+		err              string
+		wantUnauthorized bool
+	}{
+		{"response status code 401: unauthorized", true},
+		{"response status code 403: denied: requested access to the resource is denied", true},
+		{"dial tcp: connection refused", false},
+		{"manifest unknown", false},
+		{"127.0.0.1:40141/charts/app:0.1.0: not found", false},
+		{"127.0.0.1:40341/charts/app:0.1.0: not found", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err, func(t *testing.T) {
+			err := registryError("failed to pull", errors.New(tt.err))
+			if got := errors.Is(err, ErrRegistryUnauthorized); got != tt.wantUnauthorized {
+				t.Errorf("errors.Is(ErrRegistryUnauthorized) = %v, want %v", got, tt.wantUnauthorized)
+			}
+		})
+	}
+}
+
+// TestPullChartFromOCIRegistry 从真实的仓库拉取 Chart，需设置 HELM_UI_TEST_OCI_REF（如 oci://localhost:5000/charts/app:0.1.0）
+// 以及 HELM_UI_TEST_OCI_USERNAME、HELM_UI_TEST_OCI_PASSWORD
+func TestPullChartFromOCIRegistry(t *testing.T) {
+	ref := os.Getenv("HELM_UI_TEST_OCI_REF")
+	if ref == "" {
+		t.Skip("HELM_UI_TEST_OCI_REF is not set")
+	}
+	username, password := os.Getenv("HELM_UI_TEST_OCI_USERNAME"), os.Getenv("HELM_UI_TEST_OCI_PASSWORD")
+	plainHTTP := os.Getenv("HELM_UI_TEST_OCI_PLAIN_HTTP") == "true"
+
+	tests := []struct {
+		name             string
+		opts             OCIPullOptions
+		wantUnauthorized bool
+	}{
+		{"authenticated", OCIPullOptions{Username: username, Password: password, PlainHTTP: plainHTTP}, false},
+		{"wrong password", OCIPullOptions{Username: username, Password: password + "-wrong", PlainHTTP: plainHTTP}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newRenderTestService(t)
+			name, version, err := s.PullChartFromOCI(ref, tt.opts)
+			if tt.wantUnauthorized {
+				if !errors.Is(err, ErrRegistryUnauthorized) {
+					t.Errorf("PullChartFromOCI() error = %v, want %v", err, ErrRegistryUnauthorized)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.loadChart(name, version); err != nil {
+				t.Errorf("pulled chart %s-%s cannot be loaded: %v", name, version, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// reproducibleModTime 是可复现打包时写入所有文件的固定修改时间
+var reproducibleModTime = time.Unix(0, 0)
+
+// normalizeArchive 重写 Chart 包：按路径排序条目并固定修改时间与属主，
+// 使同一 Chart 目录重复打包得到逐字节相同的文件。
+func normalizeArchive(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read chart archive: %w", err)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to read chart archive: %w", err)
+	}
+	extra, comment := gz.Header.Extra, gz.Header.Comment
+
+	type entry struct {
+		header *tar.Header
+		body   []byte
+	}
+	var entries []entry
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read chart archive: %w", err)
+		}
+		body, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("failed to read chart archive: %w", err)
+		}
+		entries = append(entries, entry{header: header, body: body})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].header.Name < entries[j].header.Name
+	})
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	// 保留 helm 写入的 gzip 头信息，修改时间保持为零值
+	zw.Header.Extra = extra
+	zw.Header.Comment = comment
+
+	tw := tar.NewWriter(zw)
+	for _, e := range entries {
+		header := &tar.Header{
+			Typeflag: e.header.Typeflag,
+			Name:     e.header.Name,
+			Mode:     e.header.Mode,
+			Size:     int64(len(e.body)),
+			ModTime:  reproducibleModTime,
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write chart archive: %w", err)
+		}
+		if _, err := tw.Write(e.body); err != nil {
+			return fmt.Errorf("failed to write chart archive: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write chart archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write chart archive: %w", err)
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
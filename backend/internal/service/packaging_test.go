@@ -0,0 +1,84 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestPackageChartReproducible(t *testing.T) {
+	chartDir := t.TempDir()
+	files := map[string]string{
+		"Chart.yaml":          "apiVersion: v2\nname: demo\nversion: 0.1.0\n",
+		"values.yaml":         "replicas: 1\n",
+		"templates/cm.yaml":   "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\n",
+		"templates/a.yaml":    "# a\n",
+		"templates/NOTES.txt": "installed\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(chartDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &HelmService{tempDir: t.TempDir(), reproduciblePackaging: true}
+	pack := func() []byte {
+		path, err := s.PackageChart(chartDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	first := pack()
+	// helm 以当前时间写入条目的修改时间，间隔超过 1 秒才能暴露未规范化的时间戳
+	time.Sleep(1100 * time.Millisecond)
+	second := pack()
+
+	if !bytes.Equal(first, second) {
+		t.Fatal("packaging the same chart twice produced different archives")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(first))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !header.ModTime.Equal(reproducibleModTime) {
+			t.Errorf("%s: ModTime = %v, want %v", header.Name, header.ModTime, reproducibleModTime)
+		}
+		if header.Uid != 0 || header.Gid != 0 || header.Uname != "" || header.Gname != "" {
+			t.Errorf("%s: owner not normalized", header.Name)
+		}
+		names = append(names, header.Name)
+	}
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("entries are not sorted: %v", names)
+	}
+	if len(names) != len(files) {
+		t.Errorf("archive has %d entries, want %d", len(names), len(files))
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+	"sigs.k8s.io/yaml"
+)
+
+// policyRules 是按 conftest 约定求值的规则，deny 与 violation 视为违规，warn 仅作提示
+var policyRules = []string{"deny", "violation", "warn"}
+
+// PolicyResult 描述一条策略评估结果
+type PolicyResult struct {
+	// Policy 为触发规则所在的 Rego 包
+	Policy string `json:"policy"`
+	// Rule 为触发的规则名称：deny、violation 或 warn
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+	Resource string `json:"resource,omitempty"`
+}
+
+// Denied 判断结果是否为阻断性的违规
+func (r PolicyResult) Denied() bool {
+	return r.Rule == "deny" || r.Rule == "violation"
+}
+
+// preparedPolicyRule 是预编译的单条规则查询
+type preparedPolicyRule struct {
+	pkg   string
+	rule  string
+	query rego.PreparedEvalQuery
+}
+
+// EvaluatePolicy 按 conftest 风格使用 Rego 策略逐个评估渲染后的 manifest 文档，返回触发的规则
+func (s *HelmService) EvaluatePolicy(manifests string, policies []string) ([]PolicyResult, error) {
+	ctx := context.Background()
+
+	prepared, err := preparePolicies(ctx, policies)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []PolicyResult{}
+	for _, doc := range splitManifests(manifests) {
+		var input map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &input); err != nil || input == nil {
+			continue
+		}
+		head := parseManifestHead(doc)
+		resource := head.Kind + "/" + head.Metadata.Name
+
+		for _, p := range prepared {
+			rs, err := p.query.Eval(ctx, rego.EvalInput(input))
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate %s.%s: %w", p.pkg, p.rule, err)
+			}
+
+			for _, r := range rs {
+				for _, expr := range r.Expressions {
+					for _, msg := range policyMessages(expr.Value) {
+						results = append(results, PolicyResult{
+							Policy:   p.pkg,
+							Rule:     p.rule,
+							Message:  msg,
+							Resource: resource,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// preparePolicies 编译所有策略，并为每个包中定义的 deny/violation/warn 规则准备查询
+func preparePolicies(ctx context.Context, policies []string) ([]preparedPolicyRule, error) {
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("%w: no policies provided", ErrInvalidPolicy)
+	}
+
+	var modules []func(*rego.Rego)
+	var prepared []preparedPolicyRule
+	seen := map[string]bool{}
+	for i, policy := range policies {
+		filename := fmt.Sprintf("policy%d.rego", i)
+		module, err := ast.ParseModule(filename, policy)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+		}
+		modules = append(modules, rego.Module(filename, policy))
+
+		pkg := strings.TrimPrefix(module.Package.Path.String(), "data.")
+		for _, rule := range policyRules {
+			key := pkg + "." + rule
+			if seen[key] || !definesRule(module, rule) {
+				continue
+			}
+			seen[key] = true
+			prepared = append(prepared, preparedPolicyRule{pkg: pkg, rule: rule})
+		}
+	}
+
+	for i := range prepared {
+		options := append([]func(*rego.Rego){rego.Query(fmt.Sprintf("data.%s.%s", prepared[i].pkg, prepared[i].rule))}, modules...)
+		query, err := rego.New(options...).PrepareForEval(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+		}
+		prepared[i].query = query
+	}
+
+	return prepared, nil
+}
+
+// definesRule 判断模块中是否定义了指定名称的规则
+func definesRule(module *ast.Module, name string) bool {
+	for _, rule := range module.Rules {
+		if rule.Head.Name.String() == name || rule.Head.Ref().String() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// policyMessages 将规则结果转换为消息列表，支持字符串集合及带 msg 字段的对象
+func policyMessages(value interface{}) []string {
+	var messages []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			messages = append(messages, policyMessages(item)...)
+		}
+	case string:
+		messages = append(messages, v)
+	case map[string]interface{}:
+		if msg, ok := v["msg"].(string); ok {
+			messages = append(messages, msg)
+		} else {
+			messages = append(messages, fmt.Sprint(v))
+		}
+	case bool:
+		if v {
+			messages = append(messages, "policy rule evaluated to true")
+		}
+	}
+	return messages
+}
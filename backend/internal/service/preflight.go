@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// PreflightCheck 是预检中单项检查的结果
+type PreflightCheck struct {
+	Name string `json:"name"`
+	OK   bool   `json:"ok"`
+	// Skipped 为 true 表示该项不适用，例如 Chart 没有 values.schema.json
+	Skipped bool     `json:"skipped,omitempty"`
+	Issues  []string `json:"issues"`
+}
+
+// PreflightReport 汇总部署前的全部检查，所有检查通过时 OK 为 true
+type PreflightReport struct {
+	OK     bool             `json:"ok"`
+	Checks []PreflightCheck `json:"checks"`
+}
+
+// Preflight 依次执行 lint、schema 校验、必填字段检查与 dry-run 渲染，
+// 每项检查独立进行，某一项失败不会跳过其它检查
+func (s *HelmService) Preflight(ctx context.Context, name, version string, values map[string]interface{}, releaseName, namespace string, opts RenderOptions) (*PreflightReport, error) {
+	if _, err := s.loadChart(name, version); err != nil {
+		return nil, err
+	}
+
+	checks := []PreflightCheck{
+		s.preflightLint(name, version, values),
+		s.preflightSchema(name, version, values, namespace),
+		s.preflightRequired(ctx, name, version, values),
+		s.preflightRender(ctx, name, version, values, releaseName, namespace, opts),
+	}
+
+	report := &PreflightReport{OK: true, Checks: checks}
+	for _, check := range checks {
+		if !check.OK {
+			report.OK = false
+		}
+	}
+	return report, nil
+}
+
+// preflightLint 将 Chart 解压到临时目录后执行 lint，只有 ERROR 级别的消息导致失败
+func (s *HelmService) preflightLint(name, version string, values map[string]interface{}) PreflightCheck {
+	check := PreflightCheck{Name: "lint", OK: true, Issues: []string{}}
+
+	c, err := s.loadChart(name, version)
+	if err != nil {
+		return failedCheck(check, err)
+	}
+	tempDir, err := os.MkdirTemp("", "preflight-*")
+	if err != nil {
+		return failedCheck(check, err)
+	}
+	defer os.RemoveAll(tempDir)
+	if err := chartutil.SaveDir(c, tempDir); err != nil {
+		return failedCheck(check, err)
+	}
+
+	report := lintChartDir(filepath.Join(tempDir, c.Metadata.Name), values)
+	for _, msg := range report.Messages {
+		check.Issues = append(check.Issues, fmt.Sprintf("[%s] %s: %s", msg.Severity, msg.Path, strings.TrimSpace(msg.Message)))
+	}
+	check.OK = !report.HasErrors
+	return check
+}
+
+// preflightSchema 按 values.schema.json 校验合并后的 values，Chart 及其依赖都没有 schema 时跳过
+func (s *HelmService) preflightSchema(name, version string, values map[string]interface{}, namespace string) PreflightCheck {
+	check := PreflightCheck{Name: "schema", OK: true, Issues: []string{}}
+
+	c, err := s.loadChart(name, version)
+	if err != nil {
+		return failedCheck(check, err)
+	}
+	if !hasSchema(c) {
+		check.Skipped = true
+		return check
+	}
+
+	values, err = s.prepareValues(c.Metadata.Name, namespace, values)
+	if err != nil {
+		return failedCheck(check, err)
+	}
+	if err := chartutil.ProcessDependenciesWithMerge(c, values); err != nil {
+		return failedCheck(check, err)
+	}
+	coalesced, err := chartutil.CoalesceValues(c, values)
+	if err != nil {
+		return failedCheck(check, err)
+	}
+
+	if err := chartutil.ValidateAgainstSchema(c, coalesced); err != nil {
+		// helm 将所有 schema 错误拼接为多行文本
+		for _, line := range strings.Split(err.Error(), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				check.Issues = append(check.Issues, line)
+			}
+		}
+		check.OK = false
+	}
+	return check
+}
+
+// preflightRequired 检查 required 函数报告缺失的 values
+func (s *HelmService) preflightRequired(ctx context.Context, name, version string, values map[string]interface{}) PreflightCheck {
+	check := PreflightCheck{Name: "required", OK: true, Issues: []string{}}
+
+	missing, err := s.CheckRequired(ctx, name, version, values)
+	if err != nil {
+		return failedCheck(check, err)
+	}
+	for _, path := range missing {
+		check.Issues = append(check.Issues, "missing required value: "+path)
+	}
+	check.OK = len(missing) == 0
+	return check
+}
+
+// preflightRender 执行 dry-run 渲染，并检查渲染结果是否为合法的 YAML
+func (s *HelmService) preflightRender(ctx context.Context, name, version string, values map[string]interface{}, releaseName, namespace string, opts RenderOptions) PreflightCheck {
+	check := PreflightCheck{Name: "render", OK: true, Issues: []string{}}
+
+	result, err := s.RenderChartContext(ctx, name, version, values, releaseName, namespace, opts)
+	if err != nil {
+		return failedCheck(check, err)
+	}
+	for _, tplErr := range result.Errors {
+		check.Issues = append(check.Issues, fmt.Sprintf("%s: %s", tplErr.File, tplErr.Error))
+		check.OK = false
+	}
+	for _, doc := range ValidateManifestYAML(result.Manifest) {
+		check.Issues = append(check.Issues, fmt.Sprintf("%s: invalid YAML: %s", doc.SourceFile, doc.Error))
+		check.OK = false
+	}
+	return check
+}
+
+// failedCheck 将错误记录为检查失败
+func failedCheck(check PreflightCheck, err error) PreflightCheck {
+	check.OK = false
+	check.Issues = append(check.Issues, err.Error())
+	return check
+}
+
+// hasSchema 判断 Chart 或其任一依赖是否包含 values.schema.json
+func hasSchema(c *chart.Chart) bool {
+	if len(c.Schema) > 0 {
+		return true
+	}
+	for _, dep := range c.Dependencies() {
+		if hasSchema(dep) {
+			return true
+		}
+	}
+	return false
+}
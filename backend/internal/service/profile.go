@@ -0,0 +1,79 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"sigs.k8s.io/yaml"
+)
+
+// profileNamePattern 限制 Chart 名称与 profile 名称的字符，防止路径穿越
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// profilePath 返回指定 Chart 的 values profile 存储路径
+func (s *HelmService) profilePath(chartName, profile string) (string, error) {
+	if !profileNamePattern.MatchString(chartName) || !profileNamePattern.MatchString(profile) {
+		return "", fmt.Errorf("%w: %s/%s", ErrInvalidProfileName, chartName, profile)
+	}
+	return filepath.Join(s.dataDir, "profiles", chartName, profile+".yaml"), nil
+}
+
+// SaveProfile 保存 Chart 的命名 values profile，已存在时覆盖
+func (s *HelmService) SaveProfile(chartName, profile string, values map[string]interface{}) error {
+	path, err := s.profilePath(chartName, profile)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create profiles directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to encode profile: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write profile: %w", err)
+	}
+
+	return nil
+}
+
+// GetProfile 读取 Chart 的命名 values profile
+func (s *HelmService) GetProfile(chartName, profile string) (map[string]interface{}, error) {
+	path, err := s.profilePath(chartName, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s/%s", ErrProfileNotFound, chartName, profile)
+		}
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+
+	return parseValues(data, fmt.Sprintf("profile %s/%s", chartName, profile))
+}
+
+// DeleteProfile 删除 Chart 的命名 values profile
+func (s *HelmService) DeleteProfile(chartName, profile string) error {
+	path, err := s.profilePath(chartName, profile)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s/%s", ErrProfileNotFound, chartName, profile)
+		}
+		return fmt.Errorf("failed to delete profile: %w", err)
+	}
+
+	return nil
+}
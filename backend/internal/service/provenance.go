@@ -0,0 +1,94 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NamedValues 是带有来源名称的一组 values
+type NamedValues struct {
+	Name   string                 `json:"name"`
+	Values map[string]interface{} `json:"values"`
+}
+
+// MergeWithProvenance 按顺序合并多组 values（与 MergeValues 的规则一致，后面的优先），
+// 同时返回每个叶子路径最终取值的来源名称
+func (s *HelmService) MergeWithProvenance(sources []NamedValues) (map[string]interface{}, map[string]string, error) {
+	seen := map[string]bool{}
+	for _, source := range sources {
+		if source.Name == "" {
+			return nil, nil, fmt.Errorf("%w: values source name is required", ErrInvalidValues)
+		}
+		if seen[source.Name] {
+			return nil, nil, fmt.Errorf("%w: duplicate values source %q", ErrInvalidValues, source.Name)
+		}
+		seen[source.Name] = true
+	}
+
+	merged := map[string]interface{}{}
+	// origins 与 merged 结构相同，叶子为来源名称
+	origins := map[string]interface{}{}
+	for _, source := range sources {
+		merged = MergeValues(merged, source.Values)
+		origins = mergeOrigins(origins, source.Values, source.Name)
+	}
+
+	provenance := map[string]string{}
+	flattenOrigins(origins, "", provenance)
+	return merged, provenance, nil
+}
+
+// mergeOrigins 按 MergeValues 的规则更新来源树：两侧均为对象时递归，否则整棵子树归属于 name
+func mergeOrigins(origins, override map[string]interface{}, name string) map[string]interface{} {
+	result := make(map[string]interface{}, len(origins))
+	for k, v := range origins {
+		result[k] = v
+	}
+
+	for k, v := range override {
+		if overrideMap, ok := v.(map[string]interface{}); ok {
+			if baseMap, ok := result[k].(map[string]interface{}); ok {
+				result[k] = mergeOrigins(baseMap, overrideMap, name)
+				continue
+			}
+		}
+		result[k] = originTree(v, name)
+	}
+	return result
+}
+
+// originTree 为 value 构建所有叶子均归属于 name 的来源树，空对象视为叶子
+func originTree(value interface{}, name string) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return name
+	}
+	tree := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		tree[k] = originTree(v, name)
+	}
+	return tree
+}
+
+// flattenOrigins 将来源树展开为点分路径，key 本身包含 "." 时以 ["key"] 的形式表示
+func flattenOrigins(tree map[string]interface{}, prefix string, provenance map[string]string) {
+	for k, v := range tree {
+		path := provenancePath(prefix, k)
+		if child, ok := v.(map[string]interface{}); ok {
+			flattenOrigins(child, path, provenance)
+			continue
+		}
+		provenance[path] = v.(string)
+	}
+}
+
+// provenancePath 拼接路径
+func provenancePath(prefix, key string) string {
+	if strings.Contains(key, ".") {
+		return fmt.Sprintf("%s[%q]", prefix, key)
+	}
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// UpgradeDiff 对比已部署 release 的 manifest 与使用新 Chart 渲染出的 manifest
+func (s *HelmService) UpgradeDiff(releaseName, name, version, namespace string, values map[string]interface{}) (string, error) {
+	// 获取当前部署的 release 需要集群可达
+	if _, err := s.kubeClient(); err != nil {
+		return "", err
+	}
+
+	actionConfig, err := s.newActionConfig(namespace)
+	if err != nil {
+		return "", err
+	}
+
+	current, err := getRelease(actionConfig, releaseName, 0)
+	if err != nil {
+		return "", err
+	}
+
+	// 已部署 release 的 manifest 不包含 hook，对比时同样排除
+	rendered, err := s.RenderChart(name, version, values, releaseName, namespace, RenderOptions{NoHooks: true})
+	if err != nil {
+		return "", err
+	}
+
+	return unifiedDiff(
+		fmt.Sprintf("%s (revision %d)", releaseName, current.Version),
+		fmt.Sprintf("%s (%s-%s)", releaseName, name, version),
+		current.Manifest,
+		rendered.Manifest,
+	)
+}
+
+// getRelease 获取 release 的指定版本，revision 为 0 时返回最新版本
+func getRelease(actionConfig *action.Configuration, releaseName string, revision int) (*release.Release, error) {
+	client := action.NewGet(actionConfig)
+	client.Version = revision
+
+	rel, err := client.Run(releaseName)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrReleaseNotFound, releaseName)
+		}
+		return nil, fmt.Errorf("failed to get release: %w", err)
+	}
+	return rel, nil
+}
+
+// GetReleaseManifest 获取 release 指定版本部署的 manifest，revision 为 0 时返回最新版本
+func (s *HelmService) GetReleaseManifest(releaseName, namespace string, revision int) (string, error) {
+	if _, err := s.kubeClient(); err != nil {
+		return "", err
+	}
+
+	actionConfig, err := s.newActionConfig(namespace)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := getRelease(actionConfig, releaseName, revision)
+	if err != nil {
+		return "", err
+	}
+
+	return rel.Manifest, nil
+}
+
+// 支持的 release 排序方式
+const (
+	ReleaseSortUpdated = "updated"
+	ReleaseSortName    = "name"
+)
+
+// ReleaseSummary 描述 release 列表中的一项
+type ReleaseSummary struct {
+	Name         string    `json:"name"`
+	Namespace    string    `json:"namespace"`
+	Revision     int       `json:"revision"`
+	Status       string    `json:"status"`
+	Chart        string    `json:"chart"`
+	ChartVersion string    `json:"chartVersion"`
+	AppVersion   string    `json:"appVersion"`
+	Updated      time.Time `json:"updated"`
+}
+
+// ReleaseListOptions 定义 release 列表的过滤、排序与分页参数
+type ReleaseListOptions struct {
+	// Namespace 为空时列出所有命名空间
+	Namespace string
+	// Filter 按 release 名称子串过滤
+	Filter string
+	// Sort 为 updated（默认，按更新时间倒序）或 name
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// ReleaseList 是分页后的 release 列表，Total 为过滤后的总数
+type ReleaseList struct {
+	Releases []ReleaseSummary `json:"releases"`
+	Total    int              `json:"total"`
+}
+
+// ListReleases 列出集群中的 release，支持按命名空间和名称过滤、排序及分页
+func (s *HelmService) ListReleases(opts ReleaseListOptions) (*ReleaseList, error) {
+	if _, err := s.kubeClient(); err != nil {
+		return nil, err
+	}
+
+	actionConfig, err := s.newActionConfig(opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	client := action.NewList(actionConfig)
+	client.AllNamespaces = opts.Namespace == ""
+	client.StateMask = action.ListAll
+
+	releases, err := client.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	summaries := make([]ReleaseSummary, 0, len(releases))
+	for _, rel := range releases {
+		if opts.Filter != "" && !strings.Contains(rel.Name, opts.Filter) {
+			continue
+		}
+
+		summary := ReleaseSummary{
+			Name:      rel.Name,
+			Namespace: rel.Namespace,
+			Revision:  rel.Version,
+		}
+		if rel.Info != nil {
+			summary.Status = rel.Info.Status.String()
+			summary.Updated = rel.Info.LastDeployed.Time
+		}
+		if rel.Chart != nil && rel.Chart.Metadata != nil {
+			summary.Chart = rel.Chart.Metadata.Name
+			summary.ChartVersion = rel.Chart.Metadata.Version
+			summary.AppVersion = rel.Chart.Metadata.AppVersion
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if opts.Sort == ReleaseSortName {
+		sort.SliceStable(summaries, func(i, j int) bool {
+			if summaries[i].Name != summaries[j].Name {
+				return summaries[i].Name < summaries[j].Name
+			}
+			return summaries[i].Namespace < summaries[j].Namespace
+		})
+	} else {
+		sort.SliceStable(summaries, func(i, j int) bool {
+			return summaries[i].Updated.After(summaries[j].Updated)
+		})
+	}
+
+	// 分页
+	total := len(summaries)
+	start := opts.Offset
+	if start > total {
+		start = total
+	}
+	end := total
+	if opts.Limit > 0 && start+opts.Limit < total {
+		end = start + opts.Limit
+	}
+
+	return &ReleaseList{Releases: summaries[start:end], Total: total}, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+// DefaultReleaseService 是 helm 渲染时 .Release.Service 的值
+const DefaultReleaseService = "Helm"
+
+// renderReleaseWithService 在本地渲染 Chart，renderValues 会将 .Release.Service 设为 opts.ReleaseService，
+// 输出格式与 helm 的 client 模式 dry-run 一致
+func (s *HelmService) renderReleaseWithService(c *chart.Chart, values map[string]interface{}, releaseName, namespace string, opts RenderOptions) (*release.Release, error) {
+	if err := checkDependencies(c); err != nil {
+		return nil, err
+	}
+
+	valuesToRender, err := s.renderValues(c, values, releaseName, namespace, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRenderFailed, err)
+	}
+
+	files, err := engine.Render(c, valuesToRender)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRenderFailed, err)
+	}
+
+	// 与 helm 一致，NOTES.txt 不属于 manifest
+	var notes string
+	for file, content := range files {
+		if path.Base(file) == "NOTES.txt" {
+			if file == path.Join(c.Name(), "templates", "NOTES.txt") {
+				notes = content
+			}
+			delete(files, file)
+		}
+	}
+
+	hooks, manifests, err := releaseutil.SortManifests(files, chartutil.DefaultCapabilities.APIVersions, releaseutil.InstallOrder)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRenderFailed, err)
+	}
+
+	var b strings.Builder
+	for _, m := range manifests {
+		fmt.Fprintf(&b, "---\n# Source: %s\n%s\n", m.Name, m.Content)
+	}
+
+	return &release.Release{
+		Name:      releaseName,
+		Namespace: namespace,
+		Chart:     c,
+		Manifest:  b.String(),
+		Hooks:     hooks,
+		Info:      &release.Info{Notes: notes},
+	}, nil
+}
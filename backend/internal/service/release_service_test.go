@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestRenderChartReleaseService(t *testing.T) {
+	cm := func(name string) *chart.File {
+		return &chart.File{Name: "templates/" + name + ".yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\ndata:\n  service: {{ .Release.Service | quote }}\n")}
+	}
+	sub := &chart.Chart{
+		Metadata:  &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "sub", Version: "1.0.0"},
+		Templates: []*chart.File{cm("sub")},
+	}
+	app := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2, Name: "app", Version: "0.1.0",
+			Dependencies: []*chart.Dependency{{Name: "sub", Version: "1.0.0"}},
+		},
+		Templates: []*chart.File{
+			cm("app"),
+			{Name: "templates/NOTES.txt", Data: []byte("Managed by {{ .Release.Service }}")},
+			// fail 为 true 时整体渲染失败，best-effort 回退到逐个模板渲染
+			{Name: "templates/fail.yaml", Data: []byte(`{{ if .Values.fail }}{{ fail "boom" }}{{ end }}`)},
+		},
+	}
+	app.SetDependencies(sub)
+	s := newRenderTestService(t, app)
+
+	// 各渲染入口都应使用相同的 .Release.Service
+	renderers := map[string]func(opts RenderOptions) (string, error){
+		"render": func(opts RenderOptions) (string, error) {
+			result, err := s.RenderChart("app", "0.1.0", nil, "r", "default", opts)
+			if err != nil {
+				return "", err
+			}
+			return result.Manifest, nil
+		},
+		"best effort fallback": func(opts RenderOptions) (string, error) {
+			result, err := s.RenderChartBestEffort(context.Background(), "app", "0.1.0", map[string]interface{}{"fail": true}, "r", "default", opts)
+			if err != nil {
+				return "", err
+			}
+			if len(result.Errors) != 1 {
+				t.Errorf("got %d template errors, want 1: %v", len(result.Errors), result.Errors)
+			}
+			return result.Manifest, nil
+		},
+		"stream": func(opts RenderOptions) (string, error) {
+			var docs []string
+			err := s.RenderChartStream(context.Background(), "app", "0.1.0", nil, "r", "default", opts, func(doc string) error {
+				docs = append(docs, doc)
+				return nil
+			})
+			return strings.Join(docs, "\n---\n"), err
+		},
+	}
+
+	tests := []struct {
+		name    string
+		service string
+		want    string
+	}{
+		{"default", "", `service: "Helm"`},
+		{"explicit Helm", DefaultReleaseService, `service: "Helm"`},
+		{"custom", "Argo", `service: "Argo"`},
+	}
+
+	for _, tt := range tests {
+		for renderer, render := range renderers {
+			t.Run(tt.name+"/"+renderer, func(t *testing.T) {
+				manifest, err := render(RenderOptions{ReleaseService: tt.service})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := strings.Count(manifest, tt.want); got != 2 {
+					t.Errorf("%s found %d times, want once for the chart and once for the subchart:\n%s", tt.want, got, manifest)
+				}
+				if strings.Contains(manifest, "Managed by") {
+					t.Errorf("NOTES.txt leaked into the manifest:\n%s", manifest)
+				}
+			})
+		}
+	}
+}
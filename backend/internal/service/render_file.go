@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// RenderFile 渲染整个 Chart，仅返回指定模板文件产生的文档。
+// 模板不存在时返回 ErrTemplateNotFound，模板存在但没有输出（例如被条件排除）时返回 ErrTemplateEmpty。
+func (s *HelmService) RenderFile(ctx context.Context, name, version string, values map[string]interface{}, releaseName, namespace string, opts RenderOptions, file string) (*RenderResult, error) {
+	file = strings.TrimPrefix(file, "/")
+
+	c, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+	if !hasTemplate(c, path.Join(c.Name(), file)) {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, file)
+	}
+
+	opts.SelectedFiles = []string{file}
+	result, err := s.RenderChartContext(ctx, name, version, values, releaseName, namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.UnmatchedFiles) > 0 || strings.TrimSpace(result.Manifest) == "" {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateEmpty, file)
+	}
+
+	return result, nil
+}
+
+// hasTemplate 判断 Chart 树中是否存在完整路径为 fullPath 的模板
+func hasTemplate(c *chart.Chart, fullPath string) bool {
+	return hasTemplateWithPath(c, fullPath, c.Name())
+}
+
+// hasTemplateWithPath 按 Chart 在树中的完整路径查找模板
+func hasTemplateWithPath(c *chart.Chart, target, chartPath string) bool {
+	for _, tpl := range c.Templates {
+		if path.Join(chartPath, tpl.Name) == target {
+			return true
+		}
+	}
+	for _, dep := range c.Dependencies() {
+		if hasTemplateWithPath(dep, target, chartPath+"/charts/"+dep.Name()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// 渲染结果的状态
+const (
+	RenderStatusSuccess  = "success"
+	RenderStatusError    = "error"
+	RenderStatusTimeout  = "timeout"
+	RenderStatusCanceled = "canceled"
+)
+
+// RenderObserver 在每次渲染结束后被调用，status 为 RenderStatus* 之一
+type RenderObserver func(chart, version, status string, duration time.Duration)
+
+// SetRenderObserver 设置渲染结束后的回调，需在服务开始处理请求前调用
+func (s *HelmService) SetRenderObserver(observer RenderObserver) {
+	s.renderObserver = observer
+}
+
+// observeRender 将渲染结果交给 renderObserver，err 为 RenderChartContext 的返回值
+func (s *HelmService) observeRender(name, version string, start time.Time, err *error) {
+	// 不存在的 Chart 不计入，避免任意名称产生新的指标标签
+	if s.renderObserver == nil || errors.Is(*err, ErrChartNotFound) {
+		return
+	}
+
+	status := RenderStatusSuccess
+	switch {
+	case *err == nil:
+	case errors.Is(*err, ErrRenderTimeout):
+		status = RenderStatusTimeout
+	case errors.Is(*err, context.Canceled):
+		status = RenderStatusCanceled
+	default:
+		status = RenderStatusError
+	}
+	s.renderObserver(name, version, status, time.Since(start))
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+const (
+	// RenderTimeoutAnnotation 是 Chart.yaml 中覆盖渲染超时的注解，值为 Go duration（如 30s）
+	RenderTimeoutAnnotation = "helm-ui/render-timeout"
+	// defaultRenderTimeout 是未配置 HELM_UI_RENDER_TIMEOUT 时的渲染超时
+	defaultRenderTimeout = time.Minute
+)
+
+// loadRenderTimeout 读取全局渲染超时（HELM_UI_RENDER_TIMEOUT），0 表示不限制
+func loadRenderTimeout() time.Duration {
+	v := os.Getenv("HELM_UI_RENDER_TIMEOUT")
+	if v == "" {
+		return defaultRenderTimeout
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Printf("ignoring invalid HELM_UI_RENDER_TIMEOUT %q", v)
+		return defaultRenderTimeout
+	}
+	return d
+}
+
+// chartRenderTimeout 返回 Chart 的渲染超时：优先使用 helm-ui/render-timeout 注解，否则使用全局默认值
+func (s *HelmService) chartRenderTimeout(c *chart.Chart) time.Duration {
+	if v, ok := c.Metadata.Annotations[RenderTimeoutAnnotation]; ok {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+		log.Printf("ignoring invalid %s annotation %q on chart %s", RenderTimeoutAnnotation, v, c.Metadata.Name)
+	}
+	return s.renderTimeout
+}
+
+// renderOutcome 是后台渲染的结果
+type renderOutcome struct {
+	rel *release.Release
+	err error
+}
+
+// renderReleaseWithTimeout 在超时限制内执行 renderRelease。模板执行无法中断，超时或 ctx 取消后渲染在后台继续运行直至结束，
+// 返回的 abandoned 为 true 时调用方不应释放并发名额，由后台渲染结束后释放
+func (s *HelmService) renderReleaseWithTimeout(ctx context.Context, c *chart.Chart, values map[string]interface{}, releaseName, namespace string, opts RenderOptions) (rel *release.Release, abandoned bool, err error) {
+	return s.runRenderWithTimeout(ctx, c, func() (*release.Release, error) {
+		return s.renderRelease(c, values, releaseName, namespace, opts)
+	})
+}
+
+// runRenderWithTimeout 在 Chart 的渲染超时限制内执行 render，超时与 ctx 取消的处理同 renderReleaseWithTimeout
+func (s *HelmService) runRenderWithTimeout(ctx context.Context, c *chart.Chart, render func() (*release.Release, error)) (rel *release.Release, abandoned bool, err error) {
+	timeout := s.chartRenderTimeout(c)
+	if timeout == 0 && ctx.Done() == nil {
+		rel, err := render()
+		return rel, false, err
+	}
+
+	done := make(chan renderOutcome, 1)
+	go func() {
+		rel, err := render()
+		done <- renderOutcome{rel: rel, err: err}
+	}()
+
+	// 不限制超时时 expired 为 nil，只等待渲染结束或 ctx 取消
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case outcome := <-done:
+		return outcome.rel, false, outcome.err
+	case <-expired:
+		s.abandonRender(c, done)
+		return nil, true, fmt.Errorf("%w: %s-%s did not finish within %s", ErrRenderTimeout, c.Metadata.Name, c.Metadata.Version, timeout)
+	case <-ctx.Done():
+		s.abandonRender(c, done)
+		return nil, true, fmt.Errorf("render of %s-%s canceled: %w", c.Metadata.Name, c.Metadata.Version, ctx.Err())
+	}
+}
+
+// abandonRender 等待被放弃的渲染在后台结束后释放并发名额
+func (s *HelmService) abandonRender(c *chart.Chart, done <-chan renderOutcome) {
+	go func() {
+		<-done
+		s.renderLimiter.release()
+		log.Printf("abandoned render of chart %s-%s finished", c.Metadata.Name, c.Metadata.Version)
+	}()
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestLoadRenderTimeout(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", defaultRenderTimeout},
+		{"30s", 30 * time.Second},
+		{"0", 0},
+		{"-1s", defaultRenderTimeout},
+		{"soon", defaultRenderTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("HELM_UI_RENDER_TIMEOUT", tt.value)
+			if got := loadRenderTimeout(); got != tt.want {
+				t.Errorf("loadRenderTimeout() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChartRenderTimeout(t *testing.T) {
+	s := &HelmService{renderTimeout: time.Minute}
+
+	tests := []struct {
+		name       string
+		annotation string
+		want       time.Duration
+	}{
+		{"no annotation", "", time.Minute},
+		{"annotation overrides default", "5s", 5 * time.Second},
+		{"annotation disables timeout", "0s", 0},
+		{"invalid annotation ignored", "fast", time.Minute},
+		{"negative annotation ignored", "-5s", time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &chart.Chart{Metadata: &chart.Metadata{Name: "app", Version: "0.1.0"}}
+			if tt.annotation != "" {
+				c.Metadata.Annotations = map[string]string{RenderTimeoutAnnotation: tt.annotation}
+			}
+			if got := s.chartRenderTimeout(c); got != tt.want {
+				t.Errorf("chartRenderTimeout() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderChartTimeout(t *testing.T) {
+	newChart := func(name, timeout, template string) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{
+				APIVersion: chart.APIVersionV2, Name: name, Version: "0.1.0",
+				Annotations: map[string]string{RenderTimeoutAnnotation: timeout},
+			},
+			Templates: []*chart.File{{Name: "templates/cm.yaml", Data: []byte(template)}},
+		}
+	}
+	// slow 模板执行一个很大的循环，渲染耗时远超 1ms
+	slow := "{{- range until 3000000 }}{{ end -}}\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: slow\n"
+	fast := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: fast\n"
+	s := newRenderTestService(t,
+		newChart("slow", "1ms", slow),
+		newChart("fast", "1m", fast),
+		newChart("unlimited", "0s", slow),
+	)
+	// 全局超时极短，只有注解覆盖后才能完成渲染
+	s.renderTimeout = time.Nanosecond
+
+	tests := []struct {
+		name        string
+		chart       string
+		cancelAfter time.Duration
+		wantErr     error
+	}{
+		{"annotation timeout exceeded", "slow", 0, ErrRenderTimeout},
+		{"annotation overrides global timeout", "fast", 0, nil},
+		{"annotation disables timeout", "unlimited", 0, nil},
+		{"context canceled while rendering", "unlimited", time.Millisecond, context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelAfter > 0 {
+				time.AfterFunc(tt.cancelAfter, cancel)
+			}
+
+			_, err := s.RenderChartContext(ctx, tt.chart, "0.1.0", nil, "r", "default", RenderOptions{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RenderChartContext() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// 被放弃的渲染在后台结束后释放并发名额
+			deadline := time.Now().Add(10 * time.Second)
+			for s.renderLimiter.inFlight.Load() != 0 {
+				if time.Now().After(deadline) {
+					t.Fatal("render slot was not released")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// VersionRender 描述某个 Chart 版本的渲染结果
+type VersionRender struct {
+	Manifest   string `json:"manifest,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// RenderAllVersions 使用相同的 values 渲染 Chart 的所有已存储版本，返回版本到渲染结果的映射。
+// 单个版本渲染失败不影响其它版本，错误记录在对应版本的结果中；最多同时占用一半的渲染名额，ctx 取消时返回其错误。
+func (s *HelmService) RenderAllVersions(ctx context.Context, name string, values map[string]interface{}, releaseName, namespace string) (map[string]VersionRender, error) {
+	archives, err := s.storedVersions(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(archives) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrChartNotFound, name)
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]VersionRender, len(archives))
+		// 最多占用一半的渲染名额，避免单个请求挤占其它用户的渲染与排队名额
+		sem = make(chan struct{}, max(1, cap(s.renderLimiter.slots)/2))
+	)
+
+	for _, archive := range archives {
+		version := archive.version.Original()
+
+		// ctx 取消后不再启动新的渲染
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			start := time.Now()
+			result, err := s.RenderChartContext(ctx, name, version, values, releaseName, namespace, RenderOptions{})
+			render := VersionRender{DurationMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				render.Error = err.Error()
+			} else {
+				render.Manifest = result.Manifest
+			}
+
+			mu.Lock()
+			results[version] = render
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
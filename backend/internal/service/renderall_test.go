@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestRenderAllVersionsShare(t *testing.T) {
+	var charts []*chart.Chart
+	for _, version := range []string{"0.1.0", "0.2.0", "0.3.0", "0.4.0", "0.5.0", "0.6.0"} {
+		charts = append(charts, &chart.Chart{
+			Metadata:  &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "app", Version: version},
+			Templates: []*chart.File{{Name: "templates/cm.yaml", Data: []byte("{{- range until 300000 }}{{ end -}}\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n")}},
+		})
+	}
+
+	tests := []struct {
+		name      string
+		slots     int
+		wantShare int64
+	}{
+		{"half of the slots", 4, 2},
+		{"at least one slot", 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newRenderTestService(t, charts...)
+			s.renderLimiter = newRenderLimiter(tt.slots, 0)
+
+			// 后台观察渲染期间占用的名额峰值
+			var peak atomic.Int64
+			done := make(chan struct{})
+			go func() {
+				for {
+					select {
+					case <-done:
+						return
+					default:
+					}
+					if n := s.renderLimiter.inFlight.Load(); n > peak.Load() {
+						peak.Store(n)
+					}
+				}
+			}()
+
+			results, err := s.RenderAllVersions(context.Background(), "app", nil, "r", "default")
+			close(done)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for version, result := range results {
+				if result.Error != "" {
+					t.Errorf("version %s: %s", version, result.Error)
+				}
+			}
+			if len(results) != len(charts) {
+				t.Errorf("%d results, want %d", len(results), len(charts))
+			}
+			if got := peak.Load(); got > tt.wantShare {
+				t.Errorf("peak in-flight renders = %d, want at most %d", got, tt.wantShare)
+			}
+		})
+	}
+}
+
+func TestRenderAllVersionsCanceled(t *testing.T) {
+	s := newRenderTestService(t, &chart.Chart{
+		Metadata:  &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "app", Version: "0.1.0"},
+		Templates: []*chart.File{{Name: "templates/cm.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n")}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if _, err := s.RenderAllVersions(ctx, "app", nil, "r", "default"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("RenderAllVersions() error = %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("RenderAllVersions() returned after %s", elapsed)
+	}
+}
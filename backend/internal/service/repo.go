@@ -0,0 +1,496 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/helmpath"
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultRepoRefreshInterval 是仓库索引的默认刷新间隔
+const defaultRepoRefreshInterval = time.Hour
+
+// cachedIndex 是缓存的仓库索引
+type cachedIndex struct {
+	index       *repo.IndexFile
+	lastUpdated time.Time
+	lastError   string
+}
+
+// RepoInfo 描述一个已配置的 Helm 仓库
+type RepoInfo struct {
+	Name        string     `json:"name"`
+	URL         string     `json:"url"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+	// 凭据只返回是否配置，不返回密码和私钥
+	Username              string `json:"username,omitempty"`
+	TLSClientAuth         bool   `json:"tlsClientAuth,omitempty"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify,omitempty"`
+}
+
+// RepoService 管理 Helm 仓库配置（repositories.yaml）及其索引缓存
+type RepoService struct {
+	charts          *HelmService
+	settings        *cli.EnvSettings
+	refreshInterval time.Duration
+
+	// configMu 串行化对仓库配置文件的读改写
+	configMu sync.RWMutex
+
+	mu      sync.RWMutex
+	indexes map[string]*cachedIndex
+	hits    atomic.Int64
+	misses  atomic.Int64
+}
+
+// NewRepoService 创建新的仓库服务，charts 用于查询本地已存储的 Chart 版本
+func NewRepoService(charts *HelmService) *RepoService {
+	interval := defaultRepoRefreshInterval
+	if v := os.Getenv("HELM_UI_REPO_REFRESH_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			interval = d
+		} else {
+			log.Printf("ignoring invalid HELM_UI_REPO_REFRESH_INTERVAL %q", v)
+		}
+	}
+
+	return &RepoService{
+		charts:          charts,
+		settings:        cli.New(),
+		refreshInterval: interval,
+		indexes:         map[string]*cachedIndex{},
+	}
+}
+
+// loadRepoFile 读取仓库配置文件，文件不存在时返回空配置
+func (s *RepoService) loadRepoFile() (*repo.File, error) {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.readRepoFile()
+}
+
+// readRepoFile 读取仓库配置文件，调用方需持有 configMu
+func (s *RepoService) readRepoFile() (*repo.File, error) {
+	f, err := repo.LoadFile(s.settings.RepositoryConfig)
+	if err != nil {
+		// helm 会包装底层错误，os.IsNotExist 无法识别
+		if errors.Is(err, os.ErrNotExist) {
+			return repo.NewFile(), nil
+		}
+		return nil, fmt.Errorf("failed to load repository config: %w", err)
+	}
+	return f, nil
+}
+
+// ListRepos 列出已配置的仓库及其索引的最近刷新时间
+func (s *RepoService) ListRepos() ([]RepoInfo, error) {
+	f, err := s.loadRepoFile()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	repos := make([]RepoInfo, 0, len(f.Repositories))
+	for _, entry := range f.Repositories {
+		info := RepoInfo{
+			Name:                  entry.Name,
+			URL:                   entry.URL,
+			Username:              entry.Username,
+			TLSClientAuth:         entry.CertFile != "",
+			InsecureSkipTLSVerify: entry.InsecureSkipTLSverify,
+		}
+		if cached, ok := s.indexes[entry.Name]; ok {
+			if !cached.lastUpdated.IsZero() {
+				lastUpdated := cached.lastUpdated
+				info.LastUpdated = &lastUpdated
+			}
+			info.LastError = cached.lastError
+		}
+		repos = append(repos, info)
+	}
+
+	return repos, nil
+}
+
+// RefreshRepo 重新下载指定仓库的索引，失败时保留上一次成功获取的索引
+func (s *RepoService) RefreshRepo(name string) error {
+	// 刷新期间持有读锁，避免仓库被删除后又写回其索引
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	f, err := s.readRepoFile()
+	if err != nil {
+		return err
+	}
+
+	entry := f.Get(name)
+	if entry == nil {
+		return fmt.Errorf("%w: %s", ErrRepoNotFound, name)
+	}
+
+	index, err := s.downloadIndex(entry)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cached, ok := s.indexes[name]
+	if !ok {
+		cached = &cachedIndex{}
+		s.indexes[name] = cached
+	}
+
+	if err != nil {
+		cached.lastError = err.Error()
+		return err
+	}
+
+	cached.index = index
+	cached.lastUpdated = time.Now()
+	cached.lastError = ""
+	return nil
+}
+
+// RepoAuth 定义访问私有仓库使用的凭据，均为可选
+type RepoAuth struct {
+	Username string
+	Password string
+	// CertPEM 与 KeyPEM 为 TLS 客户端证书与私钥，CAPEM 为校验仓库证书使用的 CA
+	CertPEM string
+	KeyPEM  string
+	CAPEM   string
+	// InsecureSkipTLSVerify 为 true 时不校验仓库的 TLS 证书
+	InsecureSkipTLSVerify bool
+}
+
+// AddRepo 添加仓库：先将索引下载到临时目录确认仓库可用，再原子地写入配置文件，
+// 成功后才把索引移动到缓存目录，同名仓库已存在时不会覆盖其缓存的索引
+func (s *RepoService) AddRepo(name, repoURL string, auth RepoAuth) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: invalid name %q", ErrInvalidRepo, name)
+	}
+	if u, err := url.Parse(repoURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: invalid url %q", ErrInvalidRepo, repoURL)
+	}
+	if (auth.CertPEM == "") != (auth.KeyPEM == "") {
+		return fmt.Errorf("%w: client certificate and key must be provided together", ErrInvalidRepo)
+	}
+	if auth.CertPEM != "" {
+		if _, err := tls.X509KeyPair([]byte(auth.CertPEM), []byte(auth.KeyPEM)); err != nil {
+			return fmt.Errorf("%w: invalid client certificate: %v", ErrInvalidRepo, err)
+		}
+	}
+
+	// 同名仓库已存在时直接返回，避免无谓的下载
+	f, err := s.loadRepoFile()
+	if err != nil {
+		return err
+	}
+	if f.Has(name) {
+		return fmt.Errorf("%w: %s", ErrRepoExists, name)
+	}
+
+	// TLS 文件先写入临时目录用于下载索引，添加成功后再移动到仓库对应的目录
+	tmpDir, err := s.writeRepoTLSFiles(name, auth)
+	if err != nil {
+		return err
+	}
+	if tmpDir != "" {
+		defer os.RemoveAll(tmpDir)
+	}
+
+	// 索引同样先下载到临时目录，期间其它请求可能已添加同名仓库
+	if err := os.MkdirAll(s.settings.RepositoryCache, 0755); err != nil {
+		return fmt.Errorf("failed to create repository cache: %w", err)
+	}
+	cacheDir, err := os.MkdirTemp(s.settings.RepositoryCache, "."+name+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create repository cache: %w", err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	entry := newRepoEntry(name, repoURL, auth, tmpDir)
+	index, err := s.downloadIndexTo(entry, cacheDir)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRepo, err)
+	}
+
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	f, err = s.readRepoFile()
+	if err != nil {
+		return err
+	}
+	if f.Has(name) {
+		return fmt.Errorf("%w: %s", ErrRepoExists, name)
+	}
+
+	if tmpDir != "" {
+		dir := s.repoTLSDir(name)
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to store repository certificates: %w", err)
+		}
+		if err := os.Rename(tmpDir, dir); err != nil {
+			return fmt.Errorf("failed to store repository certificates: %w", err)
+		}
+		entry = newRepoEntry(name, repoURL, auth, dir)
+	}
+
+	f.Update(entry)
+	if err := s.writeRepoFile(f); err != nil {
+		return err
+	}
+
+	// 与 helm repo add 一致，将下载的索引文件放入缓存目录
+	for _, file := range []string{helmpath.CacheIndexFile(name), helmpath.CacheChartsFile(name)} {
+		src := filepath.Join(cacheDir, file)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if err := os.Rename(src, filepath.Join(s.settings.RepositoryCache, file)); err != nil {
+			log.Printf("failed to store repository cache %s: %v", file, err)
+		}
+	}
+
+	s.mu.Lock()
+	s.indexes[name] = &cachedIndex{index: index, lastUpdated: time.Now()}
+	s.mu.Unlock()
+	return nil
+}
+
+// repoTLSDir 返回仓库 TLS 文件所在目录，位于仓库配置文件旁
+func (s *RepoService) repoTLSDir(name string) string {
+	return filepath.Join(filepath.Dir(s.settings.RepositoryConfig), "certs", name)
+}
+
+// writeRepoTLSFiles 将 PEM 内容写入权限为 0600 的临时目录，未提供任何 TLS 文件时返回空字符串
+func (s *RepoService) writeRepoTLSFiles(name string, auth RepoAuth) (string, error) {
+	if auth.CertPEM == "" && auth.CAPEM == "" {
+		return "", nil
+	}
+
+	parent := filepath.Dir(s.repoTLSDir(name))
+	if err := os.MkdirAll(parent, 0700); err != nil {
+		return "", fmt.Errorf("failed to store repository certificates: %w", err)
+	}
+	dir, err := os.MkdirTemp(parent, "."+name+"-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to store repository certificates: %w", err)
+	}
+
+	files := map[string]string{"cert.pem": auth.CertPEM, "key.pem": auth.KeyPEM, "ca.pem": auth.CAPEM}
+	for file, content := range files {
+		if content == "" {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0600); err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("failed to store repository certificates: %w", err)
+		}
+	}
+	return dir, nil
+}
+
+// newRepoEntry 根据凭据创建仓库配置项，TLS 文件位于 tlsDir 中
+func newRepoEntry(name, repoURL string, auth RepoAuth, tlsDir string) *repo.Entry {
+	entry := &repo.Entry{
+		Name:                  name,
+		URL:                   repoURL,
+		Username:              auth.Username,
+		Password:              auth.Password,
+		InsecureSkipTLSverify: auth.InsecureSkipTLSVerify,
+	}
+	if auth.CertPEM != "" {
+		entry.CertFile = filepath.Join(tlsDir, "cert.pem")
+		entry.KeyFile = filepath.Join(tlsDir, "key.pem")
+	}
+	if auth.CAPEM != "" {
+		entry.CAFile = filepath.Join(tlsDir, "ca.pem")
+	}
+	return entry
+}
+
+// RemoveRepo 从配置文件中删除仓库，并清理其缓存的索引及下载的索引文件
+func (s *RepoService) RemoveRepo(name string) error {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	f, err := s.readRepoFile()
+	if err != nil {
+		return err
+	}
+	if !f.Remove(name) {
+		return fmt.Errorf("%w: %s", ErrRepoNotFound, name)
+	}
+	if err := s.writeRepoFile(f); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.indexes, name)
+	s.mu.Unlock()
+
+	if err := os.RemoveAll(s.repoTLSDir(name)); err != nil {
+		log.Printf("failed to remove repository certificates for %s: %v", name, err)
+	}
+
+	// 与 helm repo remove 一致，删除下载的索引文件
+	for _, file := range []string{helmpath.CacheIndexFile(name), helmpath.CacheChartsFile(name)} {
+		path := filepath.Join(s.settings.RepositoryCache, file)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("failed to remove repository cache %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// writeRepoFile 先写入临时文件再重命名，保证配置文件不会被写坏，调用方需持有 configMu
+func (s *RepoService) writeRepoFile(f *repo.File) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to encode repository config: %w", err)
+	}
+
+	path := s.settings.RepositoryConfig
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to write repository config: %w", err)
+	}
+
+	// 临时文件权限为 0600，配置中可能包含仓库凭据
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write repository config: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write repository config: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write repository config: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write repository config: %w", err)
+	}
+	return nil
+}
+
+// RefreshAll 刷新所有已配置仓库的索引
+func (s *RepoService) RefreshAll() {
+	f, err := s.loadRepoFile()
+	if err != nil {
+		log.Printf("failed to refresh repositories: %v", err)
+		return
+	}
+
+	for _, entry := range f.Repositories {
+		if err := s.RefreshRepo(entry.Name); err != nil {
+			log.Printf("failed to refresh repository %s, keeping last good index: %v", entry.Name, err)
+		}
+	}
+}
+
+// StartRefresher 启动后台协程，按配置的间隔定期刷新所有仓库索引
+func (s *RepoService) StartRefresher(ctx context.Context) {
+	go func() {
+		s.RefreshAll()
+
+		ticker := time.NewTicker(s.refreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.RefreshAll()
+			}
+		}
+	}()
+}
+
+// Index 返回指定仓库缓存的索引，尚未成功获取时返回 ErrRepoNotFound
+func (s *RepoService) Index(name string) (*repo.IndexFile, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cached, ok := s.indexes[name]
+	if !ok || cached.index == nil {
+		s.misses.Add(1)
+		return nil, fmt.Errorf("%w: index for %s is not available", ErrRepoNotFound, name)
+	}
+	s.hits.Add(1)
+	return cached.index, nil
+}
+
+// IndexCacheStats 返回仓库索引缓存的统计信息
+func (s *RepoService) IndexCacheStats() CacheStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	size := 0
+	for _, cached := range s.indexes {
+		if cached.index != nil {
+			size++
+		}
+	}
+	return CacheStats{Hits: s.hits.Load(), Misses: s.misses.Load(), Size: size}
+}
+
+// ClearIndexCache 清空缓存的仓库索引，返回清除的索引数，下次刷新时重新下载
+func (s *RepoService) ClearIndexCache() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cleared := 0
+	for _, cached := range s.indexes {
+		if cached.index != nil {
+			cleared++
+		}
+	}
+	s.indexes = map[string]*cachedIndex{}
+	return cleared
+}
+
+// downloadIndex 下载并解析仓库的 index.yaml
+func (s *RepoService) downloadIndex(entry *repo.Entry) (*repo.IndexFile, error) {
+	return s.downloadIndexTo(entry, s.settings.RepositoryCache)
+}
+
+// downloadIndexTo 下载仓库索引并写入 cacheDir
+func (s *RepoService) downloadIndexTo(entry *repo.Entry, cacheDir string) (*repo.IndexFile, error) {
+	chartRepo, err := repo.NewChartRepository(entry, getter.All(s.settings))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chart repository: %w", err)
+	}
+	chartRepo.CachePath = cacheDir
+
+	indexPath, err := chartRepo.DownloadIndexFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to download index for %s: %w", entry.Name, err)
+	}
+
+	index, err := repo.LoadIndexFile(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load index for %s: %w", entry.Name, err)
+	}
+
+	return index, nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/helmpath"
+)
+
+// newRepoTestService 创建使用临时仓库配置与缓存目录的仓库服务
+func newRepoTestService(t *testing.T) *RepoService {
+	t.Helper()
+
+	dir := t.TempDir()
+	settings := cli.New()
+	settings.RepositoryConfig = filepath.Join(dir, "repositories.yaml")
+	settings.RepositoryCache = filepath.Join(dir, "cache")
+	return &RepoService{settings: settings, indexes: map[string]*cachedIndex{}}
+}
+
+// indexServer 返回只提供包含指定 Chart 的 index.yaml 的仓库
+func indexServer(t *testing.T, chartName string) *httptest.Server {
+	t.Helper()
+
+	index := fmt.Sprintf(`apiVersion: v1
+entries:
+  %[1]s:
+  - apiVersion: v2
+    name: %[1]s
+    version: 0.1.0
+    urls:
+    - %[1]s-0.1.0.tgz
+generated: "2024-01-01T00:00:00Z"
+`, chartName)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(index))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAddRepoConcurrentSameName(t *testing.T) {
+	s := newRepoTestService(t)
+	srv := indexServer(t, "app")
+
+	const workers = 8
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = s.AddRepo("stable", srv.URL, RepoAuth{})
+		}(i)
+	}
+	wg.Wait()
+
+	added := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			added++
+		case !errors.Is(err, ErrRepoExists):
+			t.Errorf("AddRepo() error = %v, want nil or ErrRepoExists", err)
+		}
+	}
+	if added != 1 {
+		t.Errorf("%d concurrent adds succeeded, want 1", added)
+	}
+
+	repos, err := s.ListRepos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 {
+		t.Errorf("ListRepos() returned %d repos, want 1", len(repos))
+	}
+}
+
+func TestAddRepoConcurrentDistinctNames(t *testing.T) {
+	s := newRepoTestService(t)
+	srv := indexServer(t, "app")
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := s.AddRepo(fmt.Sprintf("repo%d", i), srv.URL, RepoAuth{}); err != nil {
+				t.Errorf("AddRepo(repo%d) error = %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// 配置文件的读改写被串行化，不会丢失任何一次添加
+	repos, err := s.ListRepos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != workers {
+		t.Errorf("ListRepos() returned %d repos, want %d", len(repos), workers)
+	}
+}
+
+func TestAddRepoDuplicateKeepsCachedIndex(t *testing.T) {
+	s := newRepoTestService(t)
+	original := indexServer(t, "original")
+	other := indexServer(t, "other")
+
+	if err := s.AddRepo("stable", original.URL, RepoAuth{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddRepo("stable", other.URL, RepoAuth{}); !errors.Is(err, ErrRepoExists) {
+		t.Fatalf("AddRepo() error = %v, want ErrRepoExists", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.settings.RepositoryCache, helmpath.CacheIndexFile("stable")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "original") || strings.Contains(string(data), "other") {
+		t.Errorf("cached index was overwritten by the duplicate add:\n%s", data)
+	}
+
+	// 临时下载目录不应残留
+	entries, err := os.ReadDir(s.settings.RepositoryCache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			t.Errorf("temporary cache directory %s was not removed", e.Name())
+		}
+	}
+}
+
+func TestAddRepoBasicAuth(t *testing.T) {
+	const username, password = "reader", "s3cret-password"
+	index := indexServer(t, "private")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != username || pass != password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		index.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name    string
+		repo    string
+		auth    RepoAuth
+		wantErr error
+	}{
+		{"no credentials", "anon", RepoAuth{}, ErrInvalidRepo},
+		{"wrong password", "wrong", RepoAuth{Username: username, Password: "nope"}, ErrInvalidRepo},
+		{"valid credentials", "private", RepoAuth{Username: username, Password: password}, nil},
+	}
+
+	s := newRepoTestService(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.AddRepo(tt.repo, srv.URL, tt.auth)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddRepo() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	repos, err := s.ListRepos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].Name != "private" || repos[0].Username != username {
+		t.Fatalf("ListRepos() = %+v, want only the authenticated repo", repos)
+	}
+
+	// 密码只保存在配置文件中，列表与其 JSON 序列化都不能包含密码
+	data, err := json.Marshal(repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), password) {
+		t.Errorf("ListRepos() leaked the password: %s", data)
+	}
+
+	// 刷新时同样使用保存的凭据
+	if err := s.RefreshRepo("private"); err != nil {
+		t.Errorf("RefreshRepo() error = %v", err)
+	}
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+	"text/template/parse"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+const (
+	// maxRequiredChecks 限制检查必填字段时的渲染次数
+	maxRequiredChecks = 100
+	// requiredPlaceholder 是检查过程中为缺失的必填字段填入的占位值
+	requiredPlaceholder = "REQUIRED"
+)
+
+var (
+	// requiredErrorRegex 匹配 helm 整理后的 required/fail 错误
+	requiredErrorRegex = regexp.MustCompile(`execution error at \(([^)]+)\)`)
+	// nilPointerErrorRegex 匹配 required 的参数因上级字段不存在而求值失败的错误
+	nilPointerErrorRegex = regexp.MustCompile(`template: ([^ ]+): executing "[^"]*" at <[^>]*>: nil pointer evaluating`)
+)
+
+// CheckRequired 使用给定的 values 渲染 Chart，收集所有 required 函数报告缺失的 values 路径（如 image.tag）。
+// 模板逐个渲染，每发现一个缺失字段就填入占位值后重新渲染，直到该模板不再有 required 失败，
+// 从而一次返回全部缺失字段。无法定位到 values 路径的 required 失败以其提示信息返回。
+// 整个检查受 Chart 渲染超时限制，ctx 取消或超时后立即返回
+func (s *HelmService) CheckRequired(ctx context.Context, name, version string, values map[string]interface{}) (missing []string, err error) {
+	if err := s.renderLimiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	abandoned := false
+	defer func() {
+		if !abandoned {
+			s.renderLimiter.release()
+		}
+	}()
+
+	c, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// 被放弃的检查在后台继续运行，ctx 取消后在两次渲染之间退出
+	checkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	_, abandoned, err = s.runRenderWithTimeout(ctx, c, func() (*release.Release, error) {
+		var err error
+		missing, err = s.collectRequired(checkCtx, c, values)
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// collectRequired 逐个模板填充缺失的必填字段，最多渲染 maxRequiredChecks 次
+func (s *HelmService) collectRequired(ctx context.Context, c *chart.Chart, values map[string]interface{}) ([]string, error) {
+	required := requiredCalls(c)
+
+	missing := []string{}
+	seen := map[string]bool{}
+	fills := 0
+	for _, tpl := range renderableTemplates(c) {
+		for fills < maxRequiredChecks {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			// 处理依赖会修改 Chart，每次渲染使用副本
+			c := copyChart(c)
+			valuesToRender, err := s.renderValues(c, values, "release-name", s.defaultNamespace, RenderOptions{})
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = engine.Render(isolateTemplate(c, tpl), valuesToRender)
+			if err == nil {
+				break
+			}
+
+			// 不是 required 导致的失败与模板本身的问题无关，跳过该模板
+			valuesPath, message, ok := required.lookup(err)
+			if !ok {
+				break
+			}
+			// required 的参数不是 .Values 字段时无法填充，记录提示信息后跳过该模板
+			if valuesPath == "" {
+				if !seen[message] {
+					seen[message] = true
+					missing = append(missing, message)
+				}
+				break
+			}
+
+			if !seen[valuesPath] {
+				seen[valuesPath] = true
+				missing = append(missing, valuesPath)
+			}
+			values = setValuePath(values, strings.Split(valuesPath, "."), requiredPlaceholder)
+			fills++
+		}
+	}
+
+	return missing, nil
+}
+
+// copyChart 复制依赖处理会修改的 Metadata、values 与子 Chart 列表，模板与文件内容仍与原 Chart 共享
+func copyChart(c *chart.Chart) *chart.Chart {
+	out := *c
+	metadata := *c.Metadata
+	metadata.Dependencies = make([]*chart.Dependency, len(c.Metadata.Dependencies))
+	for i, dep := range c.Metadata.Dependencies {
+		d := *dep
+		metadata.Dependencies[i] = &d
+	}
+	out.Metadata = &metadata
+	out.Values = copyValues(c.Values)
+
+	deps := make([]*chart.Chart, 0, len(c.Dependencies()))
+	for _, dep := range c.Dependencies() {
+		deps = append(deps, copyChart(dep))
+	}
+	out.SetDependencies(deps...)
+	return &out
+}
+
+// copyValues 深拷贝 values 中的 map 与切片
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		result[k] = copyValue(v)
+	}
+	return result
+}
+
+// copyValue 深拷贝单个 values 节点
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return copyValues(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = copyValue(item)
+		}
+		return items
+	default:
+		return v
+	}
+}
+
+// requiredIndex 记录 Chart 中 required 调用检查的 values 路径，路径相对于根 Chart 的 values，
+// 参数不是 .Values 字段时路径为空
+type requiredIndex struct {
+	// locations 为调用位置（file:line:col）到路径的映射
+	locations map[string]string
+	// messages 为提示信息到路径的映射，用于在 include 的模板中定位；同一信息对应多个路径时路径为空
+	messages map[string]string
+}
+
+// lookup 判断渲染错误是否由 required 引起，返回对应的 values 路径与提示信息
+func (r *requiredIndex) lookup(err error) (string, string, bool) {
+	location, message := requiredFailure(err)
+	if valuesPath, ok := r.locations[location]; ok {
+		return valuesPath, message, true
+	}
+	// include 的模板中的错误只报告最外层的调用位置，按提示信息查找
+	valuesPath, ok := r.messages[message]
+	return valuesPath, message, ok
+}
+
+// requiredFailure 从渲染错误中提取出错位置（file:line:col）与信息
+func requiredFailure(err error) (string, string) {
+	msg := err.Error()
+	if m := requiredErrorRegex.FindStringSubmatchIndex(msg); m != nil {
+		return msg[m[2]:m[3]], strings.TrimPrefix(msg[m[1]:], ": ")
+	}
+	if m := nilPointerErrorRegex.FindStringSubmatch(msg); m != nil {
+		return m[1], msg
+	}
+	return "", msg
+}
+
+// requiredCalls 解析 Chart 树中的所有模板，建立 required 调用的索引
+func requiredCalls(c *chart.Chart) *requiredIndex {
+	calls := &requiredIndex{locations: map[string]string{}, messages: map[string]string{}}
+	collectRequiredCalls(c, "", calls)
+	return calls
+}
+
+// collectRequiredCalls 收集 Chart 及其子 Chart 模板中的 required 调用，prefix 为子 Chart 在父 values 中的路径
+func collectRequiredCalls(c *chart.Chart, prefix string, calls *requiredIndex) {
+	for _, tpl := range c.Templates {
+		name := path.Join(c.ChartFullPath(), tpl.Name)
+		tree := parse.New(name)
+		tree.Mode = parse.SkipFuncCheck
+		trees := map[string]*parse.Tree{}
+		if _, err := tree.Parse(string(tpl.Data), "", "", trees); err != nil {
+			continue
+		}
+		for _, t := range trees {
+			walkRequired(t, t.Root, prefix, calls)
+		}
+	}
+
+	for _, dep := range c.Dependencies() {
+		depPrefix := dep.Name()
+		if prefix != "" {
+			depPrefix = prefix + "." + dep.Name()
+		}
+		collectRequiredCalls(dep, depPrefix, calls)
+	}
+}
+
+// walkRequired 遍历模板语法树，记录 required 调用及其参数的位置
+func walkRequired(t *parse.Tree, node parse.Node, prefix string, calls *requiredIndex) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkRequired(t, child, prefix, calls)
+		}
+	case *parse.ActionNode:
+		walkRequired(t, n.Pipe, prefix, calls)
+	case *parse.IfNode:
+		walkBranch(t, &n.BranchNode, prefix, calls)
+	case *parse.RangeNode:
+		walkBranch(t, &n.BranchNode, prefix, calls)
+	case *parse.WithNode:
+		walkBranch(t, &n.BranchNode, prefix, calls)
+	case *parse.TemplateNode:
+		walkRequired(t, n.Pipe, prefix, calls)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for i, cmd := range n.Cmds {
+			if len(cmd.Args) == 0 {
+				continue
+			}
+			if ident, ok := cmd.Args[0].(*parse.IdentifierNode); ok && ident.Ident == "required" {
+				// required "msg" .Values.x 或 .Values.x | required "msg"
+				var arg parse.Node
+				if len(cmd.Args) >= 3 {
+					arg = cmd.Args[2]
+				} else if i > 0 && len(n.Cmds[i-1].Args) == 1 {
+					arg = n.Cmds[i-1].Args[0]
+				}
+				recordRequired(t, cmd, arg, prefix, calls)
+			}
+			for _, a := range cmd.Args {
+				walkRequired(t, a, prefix, calls)
+			}
+		}
+	}
+}
+
+// walkBranch 遍历 if/range/with 节点
+func walkBranch(t *parse.Tree, n *parse.BranchNode, prefix string, calls *requiredIndex) {
+	walkRequired(t, n.Pipe, prefix, calls)
+	walkRequired(t, n.List, prefix, calls)
+	walkRequired(t, n.ElseList, prefix, calls)
+}
+
+// recordRequired 记录 required 调用（及其参数）的位置对应的 values 路径
+func recordRequired(t *parse.Tree, cmd *parse.CommandNode, arg parse.Node, prefix string, calls *requiredIndex) {
+	valuesPath := valuesFieldPath(arg, prefix)
+
+	location, _ := t.ErrorContext(cmd)
+	calls.locations[location] = valuesPath
+	if arg != nil && valuesPath != "" {
+		// 上级字段不存在时错误发生在参数求值处
+		location, _ := t.ErrorContext(arg)
+		calls.locations[location] = valuesPath
+	}
+
+	if len(cmd.Args) < 2 {
+		return
+	}
+	if message, ok := cmd.Args[1].(*parse.StringNode); ok {
+		if existing, found := calls.messages[message.Text]; found && existing != valuesPath {
+			valuesPath = ""
+		}
+		calls.messages[message.Text] = valuesPath
+	}
+}
+
+// valuesFieldPath 将 .Values.a.b 或 $.Values.a.b 转换为相对于根 Chart values 的路径，
+// global 下的字段在所有 Chart 间共享，不加子 Chart 前缀
+func valuesFieldPath(node parse.Node, prefix string) string {
+	var ident []string
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		ident = n.Ident
+	case *parse.VariableNode:
+		if len(n.Ident) == 0 || n.Ident[0] != "$" {
+			return ""
+		}
+		ident = n.Ident[1:]
+	default:
+		return ""
+	}
+
+	if len(ident) < 2 || ident[0] != "Values" {
+		return ""
+	}
+	fields := ident[1:]
+	if prefix == "" || fields[0] == "global" {
+		return strings.Join(fields, ".")
+	}
+	return prefix + "." + strings.Join(fields, ".")
+}
+
+// setValuePath 返回在 keys 路径处设置了 value 的 values 副本，沿途的 map 会被复制，不修改原 values
+func setValuePath(values map[string]interface{}, keys []string, value interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(values)+1)
+	for k, v := range values {
+		result[k] = v
+	}
+
+	if len(keys) == 1 {
+		result[keys[0]] = value
+		return result
+	}
+
+	child, _ := result[keys[0]].(map[string]interface{})
+	result[keys[0]] = setValuePath(child, keys[1:], value)
+	return result
+}
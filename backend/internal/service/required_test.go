@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestCheckRequired(t *testing.T) {
+	newChart := func(name, timeout, template string) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{
+				APIVersion: chart.APIVersionV2, Name: name, Version: "0.1.0",
+				Annotations: map[string]string{RenderTimeoutAnnotation: timeout},
+			},
+			Templates: []*chart.File{{Name: "templates/cm.yaml", Data: []byte(template)}},
+		}
+	}
+	required := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ required \"name is required\" .Values.name }}\ndata:\n  tag: {{ required \"tag is required\" .Values.image.tag }}\n"
+	// slow 模板每次渲染都执行一个很大的循环
+	slow := "{{- range until 3000000 }}{{ end -}}\n" + required
+	s := newRenderTestService(t,
+		newChart("app", "1m", required),
+		newChart("slow", "1ms", slow),
+		newChart("unlimited", "0s", slow),
+	)
+
+	tests := []struct {
+		name        string
+		chart       string
+		values      map[string]interface{}
+		cancelAfter time.Duration
+		want        []string
+		wantErr     error
+	}{
+		{"all missing", "app", nil, 0, []string{"name", "image.tag"}, nil},
+		{"partially provided", "app", map[string]interface{}{"name": "web"}, 0, []string{"image.tag"}, nil},
+		{"all provided", "app", map[string]interface{}{"name": "web", "image": map[string]interface{}{"tag": "1.0"}}, 0, []string{}, nil},
+		{"render timeout exceeded", "slow", nil, 0, nil, ErrRenderTimeout},
+		{"context canceled", "unlimited", nil, time.Millisecond, nil, context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelAfter > 0 {
+				time.AfterFunc(tt.cancelAfter, cancel)
+			}
+
+			got, err := s.CheckRequired(ctx, tt.chart, "0.1.0", tt.values)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckRequired() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckRequired() = %v, want %v", got, tt.want)
+			}
+
+			// 被放弃的检查在后台结束后释放并发名额
+			deadline := time.Now().Add(10 * time.Second)
+			for s.renderLimiter.inFlight.Load() != 0 {
+				if time.Now().After(deadline) {
+					t.Fatal("render slot was not released")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestCopyChart(t *testing.T) {
+	sub := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "sub", Version: "0.1.0"},
+		Values:   map[string]interface{}{"list": []interface{}{map[string]interface{}{"a": 1}}},
+	}
+	parent := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "app", Version: "0.1.0", Dependencies: []*chart.Dependency{{Name: "sub", Enabled: true}}},
+		Values:   map[string]interface{}{"sub": map[string]interface{}{"enabled": true}},
+	}
+	parent.AddDependency(sub)
+
+	cp := copyChart(parent)
+	cp.Metadata.Dependencies[0].Enabled = false
+	cp.Values["sub"].(map[string]interface{})["enabled"] = false
+	cp.Dependencies()[0].Values["list"].([]interface{})[0].(map[string]interface{})["a"] = 2
+	cp.SetDependencies()
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"dependency metadata", parent.Metadata.Dependencies[0].Enabled, true},
+		{"parent values", parent.Values["sub"], map[string]interface{}{"enabled": true}},
+		{"subchart values", sub.Values["list"], []interface{}{map[string]interface{}{"a": 1}}},
+		{"dependencies", len(parent.Dependencies()), 1},
+		{"copied subchart parent", len(copyChart(parent).Dependencies()[0].Parent().Dependencies()), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// ReleaseResource 描述 release 管理的一个集群对象及其当前状态
+type ReleaseResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	// Status 为对象的简要状态，例如 Running、2/3 ready，对象不存在时为 Missing
+	Status string `json:"status"`
+	// Ready 表示对象是否就绪，无法判断时省略
+	Ready *bool `json:"ready,omitempty"`
+}
+
+// GetReleaseResources 列出 release 最新版本 manifest 中的对象在集群中的当前状态
+func (s *HelmService) GetReleaseResources(releaseName, namespace string) ([]ReleaseResource, error) {
+	if _, err := s.kubeClient(); err != nil {
+		return nil, err
+	}
+
+	actionConfig, err := s.newActionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := getRelease(actionConfig, releaseName, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := actionConfig.KubeClient.Build(bytes.NewBufferString(rel.Manifest), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build release resources: %w", err)
+	}
+
+	resources := make([]ReleaseResource, 0, len(infos))
+	for _, info := range infos {
+		gvk := info.Mapping.GroupVersionKind
+		res := ReleaseResource{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Name:       info.Name,
+			Namespace:  info.Namespace,
+		}
+
+		obj, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name)
+		switch {
+		case apierrors.IsNotFound(err):
+			res.Status = "Missing"
+			ready := false
+			res.Ready = &ready
+		case err != nil:
+			res.Status = fmt.Sprintf("Unknown: %v", err)
+		default:
+			res.Status, res.Ready = objectStatus(obj)
+		}
+
+		resources = append(resources, res)
+	}
+
+	return resources, nil
+}
+
+// objectStatus 根据常见的 status 字段推断对象的简要状态
+func objectStatus(obj runtime.Object) (string, *bool) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "Unknown", nil
+	}
+	u := &unstructured.Unstructured{Object: content}
+
+	boolPtr := func(b bool) *bool { return &b }
+
+	switch u.GetKind() {
+	case "Deployment", "StatefulSet", "ReplicaSet":
+		desired, found, _ := unstructured.NestedInt64(u.Object, "spec", "replicas")
+		if !found {
+			desired = 1
+		}
+		ready, _, _ := unstructured.NestedInt64(u.Object, "status", "readyReplicas")
+		return fmt.Sprintf("%d/%d ready", ready, desired), boolPtr(ready >= desired)
+	case "DaemonSet":
+		desired, _, _ := unstructured.NestedInt64(u.Object, "status", "desiredNumberScheduled")
+		ready, _, _ := unstructured.NestedInt64(u.Object, "status", "numberReady")
+		return fmt.Sprintf("%d/%d ready", ready, desired), boolPtr(ready >= desired)
+	case "Job":
+		succeeded, _, _ := unstructured.NestedInt64(u.Object, "status", "succeeded")
+		failed, _, _ := unstructured.NestedInt64(u.Object, "status", "failed")
+		switch {
+		case succeeded > 0:
+			return "Complete", boolPtr(true)
+		case failed > 0:
+			return "Failed", boolPtr(false)
+		default:
+			return "Running", boolPtr(false)
+		}
+	}
+
+	// Pod、PVC 等对象使用 status.phase
+	if phase, found, _ := unstructured.NestedString(u.Object, "status", "phase"); found {
+		return phase, boolPtr(phase == "Running" || phase == "Bound" || phase == "Active" || phase == "Succeeded")
+	}
+
+	// 其余对象尝试使用 Ready 或 Available 条件
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t := cond["type"]; t == "Ready" || t == "Available" {
+			status := cond["status"] == "True"
+			return fmt.Sprintf("%s=%v", t, cond["status"]), boolPtr(status)
+		}
+	}
+
+	return "Exists", nil
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+// chartArchive 描述 charts 目录下某个 Chart 版本的包文件
+type chartArchive struct {
+	filename string
+	version  *semver.Version
+}
+
+// PruneChartVersions 仅保留指定 Chart 按语义化版本排序最新的 keep 个版本，返回被删除的文件名
+func (s *HelmService) PruneChartVersions(name string, keep int) ([]string, error) {
+	// 至少保留一个版本
+	if keep < 1 {
+		keep = 1
+	}
+
+	archives, err := s.storedVersions(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(archives) <= keep {
+		return []string{}, nil
+	}
+
+	pruned := []string{}
+	for _, archive := range archives[keep:] {
+		if err := s.removeArchive(name, archive); err != nil {
+			return pruned, fmt.Errorf("failed to prune %s: %w", archive.filename, err)
+		}
+		log.Printf("pruned chart %s version %s", name, archive.version.Original())
+		pruned = append(pruned, archive.filename)
+	}
+
+	return pruned, nil
+}
+
+// storedVersions 返回 charts 目录下指定 Chart 的所有版本，按语义化版本从新到旧排序
+func (s *HelmService) storedVersions(name string) ([]chartArchive, error) {
+	if s.cas != nil {
+		return s.storedVersionsCAS(name)
+	}
+
+	files, err := os.ReadDir(s.chartsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read charts directory: %w", err)
+	}
+
+	var archives []chartArchive
+	for _, file := range files {
+		if file.IsDir() || !isChartArchive(file.Name()) {
+			continue
+		}
+
+		chart, err := loader.Load(filepath.Join(s.chartsDir, file.Name()))
+		if err != nil || chart.Metadata.Name != name {
+			continue
+		}
+
+		version, err := semver.NewVersion(chart.Metadata.Version)
+		if err != nil {
+			continue
+		}
+		archives = append(archives, chartArchive{filename: file.Name(), version: version})
+	}
+
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].version.GreaterThan(archives[j].version)
+	})
+
+	return archives, nil
+}
+
+// storedVersionsCAS 从内容寻址存储的索引中返回指定 Chart 的所有版本，按语义化版本从新到旧排序
+func (s *HelmService) storedVersionsCAS(name string) ([]chartArchive, error) {
+	entries, err := s.cas.entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var archives []chartArchive
+	for _, entry := range entries {
+		if entry.name != name {
+			continue
+		}
+		version, err := semver.NewVersion(entry.version)
+		if err != nil {
+			continue
+		}
+		archives = append(archives, chartArchive{filename: filepath.Join(casBlobDir, entry.digest+".tgz"), version: version})
+	}
+
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].version.GreaterThan(archives[j].version)
+	})
+
+	return archives, nil
+}
+
+// removeArchive 删除 Chart 的某个版本，内容寻址存储下同时更新索引
+func (s *HelmService) removeArchive(name string, archive chartArchive) error {
+	if s.cas != nil {
+		return s.cas.remove(name, archive.version.Original())
+	}
+	return os.Remove(filepath.Join(s.chartsDir, archive.filename))
+}
+
+// enforceRetention 在上传成功后按 HELM_UI_MAX_VERSIONS_PER_CHART 清理旧版本，未配置时不做任何处理
+func (s *HelmService) enforceRetention(path string) {
+	if s.maxVersionsPerChart <= 0 {
+		return
+	}
+
+	chart, err := loader.Load(path)
+	if err != nil {
+		return
+	}
+
+	if _, err := s.PruneChartVersions(chart.Metadata.Name, s.maxVersionsPerChart); err != nil {
+		log.Printf("failed to enforce retention for chart %s: %v", chart.Metadata.Name, err)
+	}
+}
+
+// DeleteVersionsOptions 定义批量删除 Chart 版本的方式，Versions 与 KeepLatest 二选一
+type DeleteVersionsOptions struct {
+	// Versions 为要删除的版本
+	Versions []string
+	// KeepLatest 大于 0 时按语义化版本保留最新的 KeepLatest 个版本，删除其余版本
+	KeepLatest int
+	// Force 为 true 时允许删除 Chart 剩余的最后一个版本
+	Force bool
+}
+
+// DeleteVersionsResult 汇总批量删除的结果
+type DeleteVersionsResult struct {
+	Deleted []string          `json:"deleted"`
+	Failed  map[string]string `json:"failed"`
+}
+
+// DeleteChartVersions 批量删除 Chart 的多个版本，逐个版本报告结果；
+// 除非 opts.Force 为 true，否则会保留最新的版本，不会删除 Chart 的全部版本
+func (s *HelmService) DeleteChartVersions(name string, opts DeleteVersionsOptions) (*DeleteVersionsResult, error) {
+	archives, err := s.storedVersions(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(archives) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrChartNotFound, name)
+	}
+
+	result := &DeleteVersionsResult{Deleted: []string{}, Failed: map[string]string{}}
+
+	var targets []chartArchive
+	if opts.KeepLatest > 0 {
+		if opts.KeepLatest < len(archives) {
+			targets = archives[opts.KeepLatest:]
+		}
+	} else {
+		byVersion := map[string]chartArchive{}
+		for _, archive := range archives {
+			byVersion[archive.version.Original()] = archive
+		}
+		seen := map[string]bool{}
+		for _, version := range opts.Versions {
+			if seen[version] {
+				continue
+			}
+			seen[version] = true
+			archive, ok := byVersion[version]
+			if !ok {
+				result.Failed[version] = "version not found"
+				continue
+			}
+			targets = append(targets, archive)
+		}
+		// 按从新到旧的顺序删除，与保留最新版本的逻辑一致
+		sort.Slice(targets, func(i, j int) bool {
+			return targets[i].version.GreaterThan(targets[j].version)
+		})
+	}
+
+	// 删除全部版本时保留最新的版本
+	if len(targets) == len(archives) && !opts.Force {
+		result.Failed[targets[0].version.Original()] = "refusing to delete the last remaining version without force"
+		targets = targets[1:]
+	}
+
+	for _, archive := range targets {
+		version := archive.version.Original()
+		if !s.archiveInChartsDir(archive) {
+			result.Failed[version] = "archive path is outside the charts directory"
+			continue
+		}
+		if err := s.removeArchive(name, archive); err != nil {
+			result.Failed[version] = err.Error()
+			continue
+		}
+		log.Printf("deleted chart %s version %s", name, version)
+		result.Deleted = append(result.Deleted, version)
+	}
+
+	return result, nil
+}
+
+// archiveInChartsDir 判断包文件是否位于 charts 目录内
+func (s *HelmService) archiveInChartsDir(archive chartArchive) bool {
+	rel, err := filepath.Rel(s.chartsDir, filepath.Join(s.chartsDir, archive.filename))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// defaultClusterSessionTTL 是 kubeconfig 会话的默认有效期
+	defaultClusterSessionTTL = time.Hour
+	// defaultMaxClusterSessions 是同时存在的 kubeconfig 会话的默认上限
+	defaultMaxClusterSessions = 100
+)
+
+// ClusterSession 描述一个已创建的 kubeconfig 会话
+type ClusterSession struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// clusterSession 保存会话的 kubeconfig，仅存在于内存中
+type clusterSession struct {
+	getter    genericclioptions.RESTClientGetter
+	expiresAt time.Time
+}
+
+// clusterSessions 是按 token 索引的 kubeconfig 会话集合
+type clusterSessions struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	sessions map[string]*clusterSession
+	// max 是未过期会话数量的上限，达到上限后拒绝创建新会话
+	max int
+}
+
+// newClusterSessions 创建会话集合，有效期由 HELM_UI_CLUSTER_SESSION_TTL 配置，
+// 数量上限由 HELM_UI_MAX_CLUSTER_SESSIONS 配置
+func newClusterSessions() *clusterSessions {
+	ttl := defaultClusterSessionTTL
+	if d, err := time.ParseDuration(os.Getenv("HELM_UI_CLUSTER_SESSION_TTL")); err == nil && d > 0 {
+		ttl = d
+	}
+	return &clusterSessions{
+		ttl:      ttl,
+		sessions: map[string]*clusterSession{},
+		max:      envInt("HELM_UI_MAX_CLUSTER_SESSIONS", defaultMaxClusterSessions),
+	}
+}
+
+// CreateClusterSession 校验 kubeconfig 并创建会话，kubeconfig 只保存在内存中，不会写入磁盘或日志。
+// 未过期的会话数量达到上限时返回 ErrTooManySessions，不会挤掉已有会话
+func (s *HelmService) CreateClusterSession(kubeconfig []byte) (*ClusterSession, error) {
+	if err := validateUploadedKubeconfig(kubeconfig); err != nil {
+		return nil, err
+	}
+
+	clientConfig, err := clientcmd.NewClientConfigFromBytes(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse kubeconfig", ErrInvalidKubeconfig)
+	}
+	if _, err := clientConfig.ClientConfig(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKubeconfig, err)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate session token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	s.sessions.mu.Lock()
+	defer s.sessions.mu.Unlock()
+
+	// 顺带清理过期会话
+	now := time.Now()
+	for t, session := range s.sessions.sessions {
+		if now.After(session.expiresAt) {
+			delete(s.sessions.sessions, t)
+		}
+	}
+	if s.sessions.max > 0 && len(s.sessions.sessions) >= s.sessions.max {
+		return nil, fmt.Errorf("%w: limit is %d", ErrTooManySessions, s.sessions.max)
+	}
+
+	expiresAt := now.Add(s.sessions.ttl)
+	s.sessions.sessions[token] = &clusterSession{
+		getter:    &kubeconfigGetter{clientConfig: clientConfig},
+		expiresAt: expiresAt,
+	}
+
+	return &ClusterSession{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// validateUploadedKubeconfig 拒绝会在服务端执行命令或读取本地文件的 kubeconfig，
+// 上传的 kubeconfig 只能内联凭据
+func validateUploadedKubeconfig(kubeconfig []byte) error {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("%w: failed to parse kubeconfig", ErrInvalidKubeconfig)
+	}
+
+	for name, auth := range config.AuthInfos {
+		if auth.Exec != nil || auth.AuthProvider != nil {
+			return fmt.Errorf("%w: user %q uses an exec or auth provider plugin", ErrInvalidKubeconfig, name)
+		}
+		if auth.ClientCertificate != "" || auth.ClientKey != "" || auth.TokenFile != "" {
+			return fmt.Errorf("%w: user %q references local files, inline the credentials instead", ErrInvalidKubeconfig, name)
+		}
+	}
+	for name, cluster := range config.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return fmt.Errorf("%w: cluster %q references a local certificate file, inline it instead", ErrInvalidKubeconfig, name)
+		}
+	}
+
+	return nil
+}
+
+// ForClusterSession 返回使用会话 kubeconfig 访问集群的服务副本，会话不存在或已过期时返回 ErrSessionNotFound
+func (s *HelmService) ForClusterSession(token string) (*HelmService, error) {
+	s.sessions.mu.Lock()
+	session, ok := s.sessions.sessions[token]
+	if ok && time.Now().After(session.expiresAt) {
+		delete(s.sessions.sessions, token)
+		ok = false
+	}
+	s.sessions.mu.Unlock()
+
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+
+	s.transformersMu.RLock()
+	transformers := append([]ValueTransformer(nil), s.transformers...)
+	s.transformersMu.RUnlock()
+
+	s.analyzerRulesMu.RLock()
+	analyzerRules := append([]AnalyzerRule(nil), s.analyzerRules...)
+	s.analyzerRulesMu.RUnlock()
+
+	return &HelmService{
+		chartsDir:             s.chartsDir,
+		tempDir:               s.tempDir,
+		dataDir:               s.dataDir,
+		settings:              s.settings,
+		debug:                 s.debug,
+		namespaceDefaults:     s.namespaceDefaults,
+		renderLimiter:         s.renderLimiter,
+		defaultNamespace:      s.defaultNamespace,
+		releaseNameTemplate:   s.releaseNameTemplate,
+		transformers:          transformers,
+		analyzerRules:         analyzerRules,
+		maxVersionsPerChart:   s.maxVersionsPerChart,
+		digests:               s.digests,
+		metadata:              s.metadata,
+		reproduciblePackaging: s.reproduciblePackaging,
+		cas:                   s.cas,
+		renderTimeout:         s.renderTimeout,
+		chartDirs:             s.chartDirs,
+		renderObserver:        s.renderObserver,
+		tempMaxAge:            s.tempMaxAge,
+		sessions:              s.sessions,
+		clientGetter:          session.getter,
+	}, nil
+}
+
+// restClientGetter 返回访问集群使用的客户端配置
+func (s *HelmService) restClientGetter() genericclioptions.RESTClientGetter {
+	if s.clientGetter != nil {
+		return s.clientGetter
+	}
+	return s.settings.RESTClientGetter()
+}
+
+// kubeconfigGetter 基于内存中的 kubeconfig 实现 RESTClientGetter
+type kubeconfigGetter struct {
+	clientConfig clientcmd.ClientConfig
+}
+
+// ToRESTConfig 实现 RESTClientGetter
+func (g *kubeconfigGetter) ToRESTConfig() (*rest.Config, error) {
+	return g.clientConfig.ClientConfig()
+}
+
+// ToDiscoveryClient 实现 RESTClientGetter
+func (g *kubeconfigGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	config, err := g.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return memory.NewMemCacheClient(client), nil
+}
+
+// ToRESTMapper 实现 RESTClientGetter
+func (g *kubeconfigGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	client, err := g.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	return restmapper.NewDeferredDiscoveryRESTMapper(client), nil
+}
+
+// ToRawKubeConfigLoader 实现 RESTClientGetter
+func (g *kubeconfigGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	return g.clientConfig
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// testKubeconfig 是只内联凭据的最小 kubeconfig
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: test
+  user:
+    token: secret
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+`
+
+func TestCreateClusterSessionLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing int
+		expired  int
+		wantErr  error
+	}{
+		{"below limit", 1, 0, nil},
+		{"at limit", 2, 0, ErrTooManySessions},
+		{"expired sessions do not count", 1, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &HelmService{sessions: &clusterSessions{ttl: time.Hour, sessions: map[string]*clusterSession{}, max: 2}}
+			for i := 0; i < tt.existing; i++ {
+				session, err := s.CreateClusterSession([]byte(testKubeconfig))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if i < tt.expired {
+					s.sessions.sessions[session.Token].expiresAt = time.Now().Add(-time.Minute)
+				}
+			}
+
+			_, err := s.CreateClusterSession([]byte(testKubeconfig))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateClusterSession() error = %v, want %v", err, tt.wantErr)
+			}
+			if n := len(s.sessions.sessions); n > 2 {
+				t.Errorf("%d sessions stored, want at most 2", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+// RenderChartStream 逐个渲染 Chart 的模板，每个模板渲染完成后立即将其产生的 manifest 文档交给 emit。
+// 文档按模板路径的顺序输出，hook 资源紧随所属模板之后（NoHooks 时跳过），不支持 SortOrder。
+// 与 RenderChartBestEffort 的回退路径一样始终在本地渲染；模板渲染失败时返回错误，此前的文档已交给 emit。
+// 单个模板的执行无法中断，渲染超时与 ctx 取消在模板之间检查
+func (s *HelmService) RenderChartStream(ctx context.Context, name, version string, values map[string]interface{}, releaseName, namespace string, opts RenderOptions, emit func(doc string) error) (err error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := s.renderLimiter.acquire(ctx); err != nil {
+		return err
+	}
+	defer s.renderLimiter.release()
+	start := time.Now()
+	defer s.observeRender(name, version, start, &err)
+
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return err
+	}
+	if err := checkDependencies(chart); err != nil {
+		return err
+	}
+	if opts.Deterministic {
+		freezeChartTemplates(chart)
+	}
+
+	valuesToRender, err := s.renderValues(chart, values, releaseName, namespace, opts)
+	if err != nil {
+		return err
+	}
+
+	caps := chartutil.DefaultCapabilities
+	timeout := s.chartRenderTimeout(chart)
+	for _, tpl := range renderableTemplates(chart) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if timeout > 0 && time.Since(start) > timeout {
+			return fmt.Errorf("%w: %s-%s did not finish within %s", ErrRenderTimeout, chart.Metadata.Name, chart.Metadata.Version, timeout)
+		}
+
+		files, err := engine.Render(isolateTemplate(chart, tpl), valuesToRender)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrRenderFailed, err)
+		}
+		for file, content := range files {
+			if strings.TrimSpace(content) == "" || strings.HasSuffix(file, "NOTES.txt") {
+				delete(files, file)
+			}
+		}
+		hooks, manifests, err := releaseutil.SortManifests(files, caps.APIVersions, releaseutil.InstallOrder)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrRenderFailed, err)
+		}
+
+		var b strings.Builder
+		for _, m := range manifests {
+			fmt.Fprintf(&b, "---\n# Source: %s\n%s\n", m.Name, m.Content)
+		}
+		manifest := b.String()
+		if !opts.NoHooks {
+			manifest = appendHooks(manifest, hooks)
+		}
+
+		for _, doc := range splitManifests(filterManifests(manifest, chart.Metadata.Name, opts.SelectedFiles, opts.Resources)) {
+			if err := emit(doc); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
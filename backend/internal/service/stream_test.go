@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestRenderChartStream(t *testing.T) {
+	cm := func(name string) []byte {
+		return []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n")
+	}
+	ok := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "ok", Version: "0.1.0"},
+		Templates: []*chart.File{
+			{Name: "templates/a.yaml", Data: cm("a")},
+			{Name: "templates/b.yaml", Data: append(cm("b1"), append([]byte("---\n"), cm("b2")...)...)},
+			{Name: "templates/hook.yaml", Data: []byte("apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: h\n  annotations:\n    \"helm.sh/hook\": pre-install\n")},
+			{Name: "templates/NOTES.txt", Data: []byte("notes")},
+		},
+	}
+	broken := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "broken", Version: "0.1.0"},
+		Templates: []*chart.File{
+			{Name: "templates/a.yaml", Data: cm("a")},
+			{Name: "templates/b.yaml", Data: []byte(`{{ fail "boom" }}`)},
+			{Name: "templates/c.yaml", Data: cm("c")},
+		},
+	}
+	s := newRenderTestService(t, ok, broken)
+
+	tests := []struct {
+		name     string
+		chart    string
+		opts     RenderOptions
+		wantDocs []string
+		wantErr  error
+	}{
+		{"documents in template order", "ok", RenderOptions{}, []string{"name: a", "name: b1", "name: b2", "name: h"}, nil},
+		{"noHooks", "ok", RenderOptions{NoHooks: true}, []string{"name: a", "name: b1", "name: b2"}, nil},
+		{"selected files", "ok", RenderOptions{SelectedFiles: []string{"templates/b.yaml"}}, []string{"name: b1", "name: b2"}, nil},
+		{"failure after earlier documents", "broken", RenderOptions{}, []string{"name: a"}, ErrRenderFailed},
+		{"missing chart", "missing", RenderOptions{}, nil, ErrChartNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var docs []string
+			err := s.RenderChartStream(context.Background(), tt.chart, "0.1.0", nil, "r", "default", tt.opts, func(doc string) error {
+				docs = append(docs, doc)
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(docs) != len(tt.wantDocs) {
+				t.Fatalf("got %d documents, want %d:\n%s", len(docs), len(tt.wantDocs), strings.Join(docs, "\n---\n"))
+			}
+			for i, want := range tt.wantDocs {
+				if !strings.Contains(docs[i], want) {
+					t.Errorf("document %d = %q, want it to contain %q", i, docs[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderChartStreamStopsOnEmitError(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "many", Version: "0.1.0"},
+		Templates: []*chart.File{
+			{Name: "templates/a.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n")},
+			{Name: "templates/b.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n")},
+		},
+	}
+	s := newRenderTestService(t, c)
+
+	errGone := errors.New("client gone")
+	calls := 0
+	err := s.RenderChartStream(context.Background(), "many", "0.1.0", nil, "r", "default", RenderOptions{}, func(string) error {
+		calls++
+		return errGone
+	})
+	if !errors.Is(err, errGone) || calls != 1 {
+		t.Errorf("err = %v after %d calls, want %v after 1 call", err, calls, errGone)
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// applySubchartToggles 将 tags 与子 Chart 开关转换为 values 覆盖。
+// 声明了 condition 的子 Chart 通过设置 condition 路径的值来控制；
+// 未声明 condition 且需要禁用的子 Chart 会直接从 Chart 中移除
+func applySubchartToggles(c *chart.Chart, tags, subcharts map[string]bool) map[string]interface{} {
+	overrides := map[string]interface{}{}
+
+	if len(tags) > 0 {
+		tagValues := map[string]interface{}{}
+		for tag, enabled := range tags {
+			tagValues[tag] = enabled
+		}
+		overrides["tags"] = tagValues
+	}
+
+	for _, dep := range c.Metadata.Dependencies {
+		enabled, ok := subcharts[dependencyKey(dep)]
+		if !ok {
+			continue
+		}
+
+		if condition := firstCondition(dep.Condition); condition != "" {
+			overrides = MergeValues(overrides, pathToValues(condition, enabled))
+		} else if !enabled {
+			removeSubchart(c, dep.Name)
+		}
+	}
+
+	return overrides
+}
+
+// declaredSubcharts 返回 Chart.yaml 中声明的直接依赖名称，需在渲染处理依赖之前调用
+func declaredSubcharts(c *chart.Chart) []string {
+	var names []string
+	for _, dep := range c.Metadata.Dependencies {
+		names = append(names, dependencyKey(dep))
+	}
+	return names
+}
+
+// subchartStatus 返回渲染后各声明依赖的启用状态，被禁用的子 Chart 已在依赖处理时移除
+func subchartStatus(c *chart.Chart, declared []string) map[string]bool {
+	status := map[string]bool{}
+	for _, name := range declared {
+		status[name] = false
+	}
+	for _, sub := range c.Dependencies() {
+		if _, ok := status[sub.Name()]; ok {
+			status[sub.Name()] = true
+		}
+	}
+	return status
+}
+
+// dependencyKey 返回依赖在 values 中使用的名称，有别名时使用别名
+func dependencyKey(dep *chart.Dependency) string {
+	if dep.Alias != "" {
+		return dep.Alias
+	}
+	return dep.Name
+}
+
+// firstCondition 返回 condition 声明中的第一个路径
+func firstCondition(condition string) string {
+	first, _, _ := strings.Cut(condition, ",")
+	return strings.TrimSpace(first)
+}
+
+// pathToValues 将点分路径转换为嵌套的 values
+func pathToValues(path string, value interface{}) map[string]interface{} {
+	keys := strings.Split(path, ".")
+	result := map[string]interface{}{keys[len(keys)-1]: value}
+	for i := len(keys) - 2; i >= 0; i-- {
+		result = map[string]interface{}{keys[i]: result}
+	}
+	return result
+}
+
+// removeSubchart 从 Chart 中移除指定名称的子 Chart 及其依赖声明
+func removeSubchart(c *chart.Chart, name string) {
+	var deps []*chart.Chart
+	for _, sub := range c.Dependencies() {
+		if sub.Name() != name {
+			deps = append(deps, sub)
+		}
+	}
+	c.SetDependencies(deps...)
+
+	var metaDeps []*chart.Dependency
+	for _, dep := range c.Metadata.Dependencies {
+		if dep.Name != name {
+			metaDeps = append(metaDeps, dep)
+		}
+	}
+	c.Metadata.Dependencies = metaDeps
+}
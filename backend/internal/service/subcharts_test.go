@@ -0,0 +1,83 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestRenderChartSubchartToggles(t *testing.T) {
+	sub := func(name string) *chart.Chart {
+		return &chart.Chart{
+			Metadata:  &chart.Metadata{APIVersion: chart.APIVersionV2, Name: name, Version: "1.0.0"},
+			Templates: []*chart.File{{Name: "templates/cm.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n")}},
+		}
+	}
+	umbrella := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2, Name: "umbrella", Version: "0.1.0",
+			Dependencies: []*chart.Dependency{
+				{Name: "db", Version: "1.0.0", Condition: "db.enabled"},
+				{Name: "cache", Version: "1.0.0", Tags: []string{"backend"}},
+				{Name: "extra", Version: "1.0.0"},
+			},
+		},
+		Values: map[string]interface{}{"db": map[string]interface{}{"enabled": true}},
+		Raw:    []*chart.File{{Name: "values.yaml", Data: []byte("db:\n  enabled: true\n")}},
+	}
+	umbrella.SetDependencies(sub("db"), sub("cache"), sub("extra"))
+	s := newRenderTestService(t, umbrella)
+
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		opts   RenderOptions
+		want   map[string]bool
+	}{
+		{"defaults", nil, RenderOptions{}, map[string]bool{"db": true, "cache": true, "extra": true}},
+		{"condition in values", map[string]interface{}{"db": map[string]interface{}{"enabled": false}}, RenderOptions{}, map[string]bool{"db": false, "cache": true, "extra": true}},
+		{"subchart flag sets condition", nil, RenderOptions{Subcharts: map[string]bool{"db": false}}, map[string]bool{"db": false, "cache": true, "extra": true}},
+		{"subchart flag overrides values", map[string]interface{}{"db": map[string]interface{}{"enabled": false}}, RenderOptions{Subcharts: map[string]bool{"db": true}}, map[string]bool{"db": true, "cache": true, "extra": true}},
+		{"tag disables subchart", nil, RenderOptions{Tags: map[string]bool{"backend": false}}, map[string]bool{"db": true, "cache": false, "extra": true}},
+		{"subchart without condition removed", nil, RenderOptions{Subcharts: map[string]bool{"extra": false}}, map[string]bool{"db": true, "cache": true, "extra": false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.RenderChart("umbrella", "0.1.0", tt.values, "r", "default", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(result.Subcharts, tt.want) {
+				t.Errorf("Subcharts = %v, want %v", result.Subcharts, tt.want)
+			}
+			for name, enabled := range tt.want {
+				if got := strings.Contains(result.Manifest, "name: "+name+"\n"); got != enabled {
+					t.Errorf("manifest of %s present = %v, want %v", name, got, enabled)
+				}
+			}
+		})
+	}
+}
+
+func TestPathToValues(t *testing.T) {
+	tests := []struct {
+		path string
+		want map[string]interface{}
+	}{
+		{"enabled", map[string]interface{}{"enabled": true}},
+		{"db.enabled", map[string]interface{}{"db": map[string]interface{}{"enabled": true}}},
+		{"a.b.c", map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"c": true}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := pathToValues(tt.path, true); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pathToValues(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
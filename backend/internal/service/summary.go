@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/release"
+	"sigs.k8s.io/yaml"
+)
+
+// ResourceSummary 描述渲染结果中的一个资源
+type ResourceSummary struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	APIVersion string `json:"apiVersion"`
+	SourceFile string `json:"sourceFile"`
+	// Hook 为 true 表示该资源是 helm hook，而不是 release 的常规资源
+	Hook bool `json:"hook"`
+}
+
+// ManifestStats 汇总渲染结果中的文档数量、各 kind 的数量与总字节数
+type ManifestStats struct {
+	DocumentCount int            `json:"documentCount"`
+	ByKind        map[string]int `json:"byKind"`
+	Bytes         int            `json:"bytes"`
+}
+
+// SummarizeManifest 统计渲染结果，只包含注释或空白的文档不计入
+func SummarizeManifest(manifest string) ManifestStats {
+	stats := ManifestStats{ByKind: map[string]int{}, Bytes: len(manifest)}
+	for _, m := range splitManifests(manifest) {
+		if isEmptyManifest(m) {
+			continue
+		}
+		kind := parseManifestHead(m).Kind
+		if kind == "" {
+			kind = "Unknown"
+		}
+		stats.DocumentCount++
+		stats.ByKind[kind]++
+	}
+	return stats
+}
+
+// isEmptyManifest 判断文档是否只包含注释或空白
+func isEmptyManifest(manifest string) bool {
+	for _, line := range strings.Split(manifest, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+// RenderSummary 渲染 Chart，仅返回生成的资源列表而不返回完整的 manifest
+func (s *HelmService) RenderSummary(ctx context.Context, name, version string, values map[string]interface{}, releaseName, namespace string, opts RenderOptions) ([]ResourceSummary, error) {
+	result, err := s.RenderChartContext(ctx, name, version, values, releaseName, namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeManifests(result.Manifest), nil
+}
+
+// summarizeManifests 解析 manifest 中每个资源的 kind、名称与来源模板
+func summarizeManifests(manifest string) []ResourceSummary {
+	resources := []ResourceSummary{}
+	for _, m := range splitManifests(manifest) {
+		head := parseManifestHead(m)
+		if head.Kind == "" {
+			continue
+		}
+		_, hook := head.Metadata.Annotations[release.HookAnnotation]
+		resources = append(resources, ResourceSummary{
+			Kind:       head.Kind,
+			Name:       head.Metadata.Name,
+			APIVersion: head.APIVersion,
+			SourceFile: manifestSource(m),
+			Hook:       hook,
+		})
+	}
+	return resources
+}
+
+// manifestSource 返回 manifest 开头 "# Source:" 注释中的模板路径
+func manifestSource(manifest string) string {
+	for _, line := range strings.Split(manifest, "\n") {
+		if source, ok := strings.CutPrefix(strings.TrimSpace(line), "# Source:"); ok {
+			return strings.TrimSpace(source)
+		}
+	}
+	return ""
+}
+
+// RenderedDocument 是渲染结果中的单个 YAML 文档及其元数据
+type RenderedDocument struct {
+	SourceFile string `json:"sourceFile"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	APIVersion string `json:"apiVersion"`
+	IsHook     bool   `json:"isHook"`
+	YAML       string `json:"yaml"`
+}
+
+// RenderDocuments 渲染 Chart 并按文档拆分，跳过只包含注释或空白的文档
+func (s *HelmService) RenderDocuments(ctx context.Context, name, version string, values map[string]interface{}, releaseName, namespace string, opts RenderOptions) ([]RenderedDocument, error) {
+	result, err := s.RenderChartContext(ctx, name, version, values, releaseName, namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+	return splitDocuments(result.Manifest), nil
+}
+
+// splitDocuments 拆分 manifest 并解析每个文档的元数据；字段缺失或类型不符时对应字段留空
+func splitDocuments(manifest string) []RenderedDocument {
+	documents := []RenderedDocument{}
+	for _, m := range splitManifests(manifest) {
+		if isEmptyManifest(m) {
+			continue
+		}
+
+		doc := RenderedDocument{SourceFile: manifestSource(m), YAML: m}
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(m), &obj); err == nil {
+			doc.Kind, _ = obj["kind"].(string)
+			doc.APIVersion, _ = obj["apiVersion"].(string)
+			if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+				doc.Name, _ = metadata["name"].(string)
+				if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+					_, doc.IsHook = annotations[release.HookAnnotation]
+				}
+			}
+		}
+		documents = append(documents, doc)
+	}
+	return documents
+}
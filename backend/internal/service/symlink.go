@@ -0,0 +1,58 @@
+package service
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// CheckArchiveEntries 检查 Chart 包中是否包含符号链接、硬链接或指向包外的路径，存在时返回 ErrInvalidChart。
+// helm 的 loader 会把链接当作普通文件读取，这里显式拒绝，避免解压到磁盘时写到目录之外
+func CheckArchiveEntries(r io.Reader) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidChart, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidChart, err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeSymlink, tar.TypeLink:
+			return fmt.Errorf("%w: %s is a link", ErrInvalidChart, header.Name)
+		}
+		name := strings.ReplaceAll(header.Name, `\`, "/")
+		if path.IsAbs(name) || strings.HasPrefix(path.Clean(name), "..") {
+			return fmt.Errorf("%w: %s is outside the chart", ErrInvalidChart, header.Name)
+		}
+	}
+}
+
+// checkDirSymlinks 检查 Chart 目录中是否包含符号链接，存在时返回 ErrInvalidChart。
+// helm 加载目录时会跟随符号链接，可能读取到目录之外的文件
+func checkDirSymlinks(dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			rel, _ := filepath.Rel(dir, p)
+			return fmt.Errorf("%w: %s is a symlink", ErrInvalidChart, rel)
+		}
+		return nil
+	})
+}
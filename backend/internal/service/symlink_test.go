@@ -0,0 +1,134 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tarGz 使用给定的 tar 头创建 gzip 压缩的归档，普通文件的内容为空
+func tarGz(t *testing.T, headers ...*tar.Header) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, h := range headers {
+		if h.Mode == 0 {
+			h.Mode = 0o644
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCheckArchiveEntries(t *testing.T) {
+	chartYAML := &tar.Header{Name: "demo/Chart.yaml", Typeflag: tar.TypeReg}
+
+	tests := []struct {
+		name    string
+		entry   *tar.Header
+		wantErr bool
+	}{
+		{"regular file", &tar.Header{Name: "demo/values.yaml", Typeflag: tar.TypeReg}, false},
+		{"directory", &tar.Header{Name: "demo/templates/", Typeflag: tar.TypeDir}, false},
+		{"backslash inside chart", &tar.Header{Name: `demo\templates\cm.yaml`, Typeflag: tar.TypeReg}, false},
+		{"symlink", &tar.Header{Name: "demo/values.yaml", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}, true},
+		{"relative symlink", &tar.Header{Name: "demo/values.yaml", Typeflag: tar.TypeSymlink, Linkname: "../../secret"}, true},
+		{"hardlink", &tar.Header{Name: "demo/values.yaml", Typeflag: tar.TypeLink, Linkname: "demo/Chart.yaml"}, true},
+		{"parent traversal", &tar.Header{Name: "../evil.yaml", Typeflag: tar.TypeReg}, true},
+		{"nested parent traversal", &tar.Header{Name: "demo/../../evil.yaml", Typeflag: tar.TypeReg}, true},
+		{"absolute path", &tar.Header{Name: "/etc/cron.d/evil", Typeflag: tar.TypeReg}, true},
+		{"backslash traversal", &tar.Header{Name: `..\evil.yaml`, Typeflag: tar.TypeReg}, true},
+		{"backslash absolute path", &tar.Header{Name: `\etc\evil`, Typeflag: tar.TypeReg}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tarGz(t, chartYAML, tt.entry)
+			err := CheckArchiveEntries(bytes.NewReader(data))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidChart) {
+					t.Fatalf("CheckArchiveEntries() error = %v, want ErrInvalidChart", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckArchiveEntries() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckArchiveEntriesNotGzip(t *testing.T) {
+	if err := CheckArchiveEntries(bytes.NewReader([]byte("not a chart"))); !errors.Is(err, ErrInvalidChart) {
+		t.Fatalf("CheckArchiveEntries() error = %v, want ErrInvalidChart", err)
+	}
+}
+
+func TestCheckDirSymlinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, dir string)
+		wantErr bool
+	}{
+		{
+			name:  "regular files",
+			setup: func(t *testing.T, dir string) {},
+		},
+		{
+			name: "symlinked file",
+			setup: func(t *testing.T, dir string) {
+				if err := os.Symlink("/etc/passwd", filepath.Join(dir, "values.yaml")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "symlinked directory",
+			setup: func(t *testing.T, dir string) {
+				if err := os.Symlink(os.TempDir(), filepath.Join(dir, "templates", "linked")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(dir, "templates"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("apiVersion: v2\nname: demo\nversion: 0.1.0\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			tt.setup(t, dir)
+
+			err := checkDirSymlinks(dir)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidChart) {
+					t.Fatalf("checkDirSymlinks() error = %v, want ErrInvalidChart", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkDirSymlinks() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// defaultTempMaxAge 是未配置 HELM_UI_TEMP_MAX_AGE 时临时文件被清理前保留的时长
+	defaultTempMaxAge = time.Hour
+	// tempSweepInterval 是后台清理临时目录的间隔
+	tempSweepInterval = 10 * time.Minute
+)
+
+// TempFile 描述临时目录中的一个文件或目录
+type TempFile struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Dir     bool      `json:"dir"`
+	// Stale 为 true 表示超过保留时长，下次清理时会被删除
+	Stale bool `json:"stale"`
+}
+
+// loadTempMaxAge 读取临时文件的保留时长（HELM_UI_TEMP_MAX_AGE）
+func loadTempMaxAge() time.Duration {
+	v := os.Getenv("HELM_UI_TEMP_MAX_AGE")
+	if v == "" {
+		return defaultTempMaxAge
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("ignoring invalid HELM_UI_TEMP_MAX_AGE %q", v)
+		return defaultTempMaxAge
+	}
+	return d
+}
+
+// ListTempFiles 列出临时目录中的文件，目录的大小为其中所有文件大小之和。
+// 分片上传目录由上传流程按自身的过期时间管理，不在此列出
+func (s *HelmService) ListTempFiles() ([]TempFile, error) {
+	entries, err := os.ReadDir(s.tempDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []TempFile{}, nil
+		}
+		return nil, fmt.Errorf("failed to read temp directory: %w", err)
+	}
+
+	files := []TempFile{}
+	for _, entry := range entries {
+		path := filepath.Join(s.tempDir, entry.Name())
+		if path == s.uploadsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		file := TempFile{
+			Name:    entry.Name(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			Dir:     entry.IsDir(),
+			Stale:   time.Since(info.ModTime()) > s.tempMaxAge,
+		}
+		if entry.IsDir() {
+			file.Size = dirSize(path)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// CleanTempFiles 删除临时目录中超过保留时长的文件与过期的分片上传，返回被删除的名称
+func (s *HelmService) CleanTempFiles() ([]string, error) {
+	files, err := s.ListTempFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []string{}
+	for _, file := range files {
+		if !file.Stale {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(s.tempDir, file.Name)); err != nil {
+			log.Printf("failed to remove temp file %s: %v", file.Name, err)
+			continue
+		}
+		log.Printf("removed stale temp file %s (%d bytes, modified %s)", file.Name, file.Size, file.ModTime.Format(time.RFC3339))
+		removed = append(removed, file.Name)
+	}
+
+	s.cleanupStaleUploads()
+	return removed, nil
+}
+
+// StartTempJanitor 在后台定期清理临时目录，ctx 取消时停止
+func (s *HelmService) StartTempJanitor(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(tempSweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.CleanTempFiles(); err != nil {
+					log.Printf("failed to clean temp directory: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// dirSize 返回目录中所有文件大小之和
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"text/template/parse"
+)
+
+// TemplateReferences 返回 Chart 中每个模板文件通过 include 与 template 引用的命名模板（如 _helpers.tpl 中 define 的模板），
+// key 为模板文件路径（如 templates/deployment.yaml），引用按名称排序去重。
+// 只能识别名称为字符串常量的引用，include (printf "%s.name" .Chart.Name) 这类动态名称会被忽略；
+// 无法解析的模板返回空列表
+func (s *HelmService) TemplateReferences(name, version string) (map[string][]string, error) {
+	c, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := map[string][]string{}
+	for _, tpl := range c.Templates {
+		if !strings.HasPrefix(tpl.Name, "templates/") {
+			continue
+		}
+
+		names := map[string]bool{}
+		tree := parse.New(tpl.Name)
+		tree.Mode = parse.SkipFuncCheck
+		trees := map[string]*parse.Tree{}
+		if _, err := tree.Parse(string(tpl.Data), "", "", trees); err == nil {
+			for _, t := range trees {
+				walkTemplateRefs(t.Root, names)
+			}
+		}
+
+		list := make([]string, 0, len(names))
+		for n := range names {
+			list = append(list, n)
+		}
+		sort.Strings(list)
+		refs[tpl.Name] = list
+	}
+
+	return refs, nil
+}
+
+// walkTemplateRefs 遍历模板语法树，收集引用的命名模板
+func walkTemplateRefs(node parse.Node, names map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkTemplateRefs(child, names)
+		}
+	case *parse.ActionNode:
+		walkTemplateRefs(n.Pipe, names)
+	case *parse.IfNode:
+		walkTemplateRefsBranch(&n.BranchNode, names)
+	case *parse.RangeNode:
+		walkTemplateRefsBranch(&n.BranchNode, names)
+	case *parse.WithNode:
+		walkTemplateRefsBranch(&n.BranchNode, names)
+	case *parse.TemplateNode:
+		names[n.Name] = true
+		walkTemplateRefs(n.Pipe, names)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			walkTemplateRefs(cmd, names)
+		}
+	case *parse.CommandNode:
+		// include "name" . 的第一个参数为模板名称
+		if len(n.Args) >= 2 {
+			if ident, ok := n.Args[0].(*parse.IdentifierNode); ok && ident.Ident == "include" {
+				if name, ok := n.Args[1].(*parse.StringNode); ok {
+					names[name.Text] = true
+				}
+			}
+		}
+		for _, arg := range n.Args {
+			walkTemplateRefs(arg, names)
+		}
+	}
+}
+
+// walkTemplateRefsBranch 遍历 if/range/with 节点
+func walkTemplateRefsBranch(n *parse.BranchNode, names map[string]bool) {
+	walkTemplateRefs(n.Pipe, names)
+	walkTemplateRefs(n.List, names)
+	walkTemplateRefs(n.ElseList, names)
+}
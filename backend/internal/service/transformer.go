@@ -0,0 +1,102 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValueTransformer 在渲染前对 values 进行转换，用于实现组织内统一的 values 规范化
+type ValueTransformer interface {
+	Transform(chartName string, values map[string]interface{}) (map[string]interface{}, error)
+}
+
+// RegisterTransformer 注册 values 转换器，转换器按注册顺序依次执行
+func (s *HelmService) RegisterTransformer(t ValueTransformer) {
+	s.transformersMu.Lock()
+	defer s.transformersMu.Unlock()
+	s.transformers = append(s.transformers, t)
+}
+
+// applyTransformers 依次执行已注册的 values 转换器
+func (s *HelmService) applyTransformers(chartName string, values map[string]interface{}) (map[string]interface{}, error) {
+	s.transformersMu.RLock()
+	transformers := append([]ValueTransformer(nil), s.transformers...)
+	s.transformersMu.RUnlock()
+
+	for _, t := range transformers {
+		transformed, err := t.Transform(chartName, values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to transform values: %w", err)
+		}
+		values = transformed
+	}
+	return values, nil
+}
+
+// NoopTransformer 原样返回 values
+type NoopTransformer struct{}
+
+// Transform 实现 ValueTransformer
+func (NoopTransformer) Transform(_ string, values map[string]interface{}) (map[string]interface{}, error) {
+	return values, nil
+}
+
+// RegistryAliasTransformer 将镜像地址中的仓库简写展开为完整的镜像仓库地址，
+// 例如别名 corp=registry.example.com 会把 corp/app 展开为 registry.example.com/app
+type RegistryAliasTransformer struct {
+	Aliases map[string]string
+}
+
+// NewRegistryAliasTransformer 从 "alias=registry,alias2=registry2" 形式的配置创建转换器
+func NewRegistryAliasTransformer(spec string) *RegistryAliasTransformer {
+	aliases := map[string]string{}
+	for _, pair := range strings.Split(spec, ",") {
+		alias, registry, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && alias != "" && registry != "" {
+			aliases[alias] = strings.TrimSuffix(registry, "/")
+		}
+	}
+	return &RegistryAliasTransformer{Aliases: aliases}
+}
+
+// Transform 实现 ValueTransformer，展开 image 与 image.repository 中的仓库简写
+func (t *RegistryAliasTransformer) Transform(_ string, values map[string]interface{}) (map[string]interface{}, error) {
+	return t.expand(values), nil
+}
+
+// expand 递归展开 values 中的镜像地址
+func (t *RegistryAliasTransformer) expand(values map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			expanded := t.expand(value)
+			if k == "image" {
+				if repo, ok := expanded["repository"].(string); ok {
+					expanded["repository"] = t.expandImage(repo)
+				}
+			}
+			result[k] = expanded
+		case string:
+			if k == "image" {
+				value = t.expandImage(value)
+			}
+			result[k] = value
+		default:
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// expandImage 展开单个镜像地址的仓库简写
+func (t *RegistryAliasTransformer) expandImage(image string) string {
+	alias, rest, ok := strings.Cut(image, "/")
+	if !ok {
+		return image
+	}
+	if registry, ok := t.Aliases[alias]; ok {
+		return registry + "/" + rest
+	}
+	return image
+}
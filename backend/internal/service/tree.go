@@ -0,0 +1,95 @@
+package service
+
+import (
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// ChartNode 描述依赖树中的一个 Chart
+type ChartNode struct {
+	Name      string       `json:"name"`
+	Version   string       `json:"version"`
+	Alias     string       `json:"alias,omitempty"`
+	Condition string       `json:"condition,omitempty"`
+	Tags      []string     `json:"tags,omitempty"`
+	Enabled   *bool        `json:"enabled,omitempty"`
+	Children  []*ChartNode `json:"children"`
+}
+
+// ChartTree 返回 Chart 及其子 Chart 组成的依赖树
+func (s *HelmService) ChartTree(name, version string) (*ChartNode, error) {
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildChartNode(chart, nil, chart.Values), nil
+}
+
+// buildChartNode 递归构建依赖树节点，dep 为父 Chart 中对该 Chart 的依赖声明
+func buildChartNode(c *chart.Chart, dep *chart.Dependency, parentValues map[string]interface{}) *ChartNode {
+	node := &ChartNode{
+		Name:     c.Metadata.Name,
+		Version:  c.Metadata.Version,
+		Children: []*ChartNode{},
+	}
+
+	if dep != nil {
+		node.Alias = dep.Alias
+		node.Condition = dep.Condition
+		node.Tags = dep.Tags
+		if enabled, ok := dependencyEnabled(dep, parentValues); ok {
+			node.Enabled = &enabled
+		}
+	}
+
+	for _, sub := range c.Dependencies() {
+		node.Children = append(node.Children, buildChartNode(sub, findDependency(c, sub), c.Values))
+	}
+
+	return node
+}
+
+// findDependency 在 Chart.yaml 的依赖声明中查找与子 Chart 对应的条目
+func findDependency(parent, sub *chart.Chart) *chart.Dependency {
+	for _, dep := range parent.Metadata.Dependencies {
+		if dep.Name == sub.Metadata.Name {
+			return dep
+		}
+	}
+	return nil
+}
+
+// dependencyEnabled 按 helm 的规则根据 values 判断依赖是否启用：先看 tags，再由 condition 覆盖。
+// 第二个返回值表示能否根据 values 确定启用状态
+func dependencyEnabled(dep *chart.Dependency, values chartutil.Values) (bool, bool) {
+	enabled, determined := true, false
+
+	if tags, err := values.Table("tags"); err == nil && len(dep.Tags) > 0 {
+		var hasTrue, hasFalse bool
+		for _, tag := range dep.Tags {
+			if b, ok := tags[tag].(bool); ok {
+				hasTrue = hasTrue || b
+				hasFalse = hasFalse || !b
+			}
+		}
+		if hasTrue || hasFalse {
+			enabled, determined = hasTrue || !hasFalse, true
+		}
+	}
+
+	for _, condition := range strings.Split(strings.TrimSpace(dep.Condition), ",") {
+		if condition == "" {
+			continue
+		}
+		if v, err := values.PathValue(condition); err == nil {
+			if b, ok := v.(bool); ok {
+				return b, true
+			}
+		}
+	}
+
+	return enabled, determined
+}
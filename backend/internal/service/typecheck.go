@@ -0,0 +1,94 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// valueTypeWarnings 比较用户提交的 values 与 Chart 默认 values 的类型，返回类型不一致的警告，
+// 如 image.tag 默认是字符串而提交的是数字（1.20 会被渲染为 1.2）。子 Chart 的 values 与其自身的默认值比较
+func valueTypeWarnings(c *chart.Chart, values map[string]interface{}) []string {
+	var warnings []string
+	collectTypeWarnings(c, c.Values, values, "", &warnings)
+	return warnings
+}
+
+// collectTypeWarnings 递归比较 path 处的默认 values 与用户 values
+func collectTypeWarnings(c *chart.Chart, defaults, values map[string]interface{}, path string, warnings *[]string) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		childPath := k
+		if path != "" {
+			childPath = path + "." + k
+		}
+		value := values[k]
+
+		def, ok := defaults[k]
+		if !ok {
+			// 子 Chart 的 values 以子 Chart 名称为 key，与子 Chart 的默认值比较
+			if sub := subchartByName(c, k); sub != nil && path == "" {
+				if m, ok := value.(map[string]interface{}); ok {
+					collectTypeWarnings(sub, sub.Values, m, "", warnings)
+				}
+			}
+			continue
+		}
+
+		expected, actual := valueKind(def), valueKind(value)
+		if expected == "" || actual == "" {
+			continue
+		}
+		if expected != actual {
+			*warnings = append(*warnings, fmt.Sprintf("%s expected %s, got %s", prefixedPath(c, childPath), expected, actual))
+			continue
+		}
+		if expected == "object" {
+			collectTypeWarnings(c, def.(map[string]interface{}), value.(map[string]interface{}), childPath, warnings)
+		}
+	}
+}
+
+// subchartByName 返回名称为 name 的直接子 Chart
+func subchartByName(c *chart.Chart, name string) *chart.Chart {
+	for _, dep := range c.Dependencies() {
+		if dep.Name() == name {
+			return dep
+		}
+	}
+	return nil
+}
+
+// prefixedPath 返回 values 路径相对于根 Chart 的完整路径
+func prefixedPath(c *chart.Chart, path string) string {
+	for p := c; !p.IsRoot(); p = p.Parent() {
+		path = p.Name() + "." + path
+	}
+	return path
+}
+
+// valueKind 返回 values 中值的类型名称，null 返回空字符串表示不做比较
+func valueKind(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return ""
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
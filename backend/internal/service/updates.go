@@ -0,0 +1,64 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// LatestStoredVersion 返回 charts 目录下指定 Chart 的最高版本
+func (s *HelmService) LatestStoredVersion(name string) (string, error) {
+	archives, err := s.storedVersions(name)
+	if err != nil {
+		return "", err
+	}
+	if len(archives) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrChartNotFound, name)
+	}
+	return archives[0].version.Original(), nil
+}
+
+// CheckForUpdates 比较本地存储的最高版本与所有已配置仓库索引中的最新版本，
+// 没有任何仓库包含该 Chart 时 latest 为空
+func (s *RepoService) CheckForUpdates(chartName string) (current, latest string, updateAvailable bool, err error) {
+	current, err = s.charts.LatestStoredVersion(chartName)
+	if err != nil {
+		return "", "", false, err
+	}
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return "", "", false, fmt.Errorf("invalid local version %s: %w", current, err)
+	}
+
+	f, err := s.loadRepoFile()
+	if err != nil {
+		return "", "", false, err
+	}
+
+	// 在所有仓库中选取最新的版本，尚未获取到索引的仓库跳过
+	var latestVersion *semver.Version
+	for _, entry := range f.Repositories {
+		index, err := s.Index(entry.Name)
+		if err != nil {
+			continue
+		}
+		chartVersion, err := index.Get(chartName, "")
+		if err != nil {
+			continue
+		}
+		v, err := semver.NewVersion(chartVersion.Version)
+		if err != nil {
+			continue
+		}
+		if latestVersion == nil || v.GreaterThan(latestVersion) {
+			latestVersion = v
+			latest = chartVersion.Version
+		}
+	}
+
+	if latestVersion == nil {
+		return current, "", false, nil
+	}
+
+	return current, latest, latestVersion.GreaterThan(currentVersion), nil
+}
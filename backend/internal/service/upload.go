@@ -0,0 +1,209 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+// staleUploadAge 是未完成的分片上传被清理前保留的时长
+const staleUploadAge = 24 * time.Hour
+
+// uploadIDPattern 用于校验上传 ID，防止路径穿越
+var uploadIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// uploadsDir 返回分片上传临时文件所在目录
+func (s *HelmService) uploadsDir() string {
+	return filepath.Join(s.tempDir, "uploads")
+}
+
+// uploadPath 返回指定上传 ID 对应的临时文件路径
+func (s *HelmService) uploadPath(id string) (string, error) {
+	if !uploadIDPattern.MatchString(id) {
+		return "", fmt.Errorf("%w: %s", ErrUploadNotFound, id)
+	}
+	return filepath.Join(s.uploadsDir(), id+".part"), nil
+}
+
+// InitUpload 创建一个新的分片上传，返回上传 ID
+func (s *HelmService) InitUpload() (string, error) {
+	if err := os.MkdirAll(s.uploadsDir(), 0755); err != nil {
+		return "", fmt.Errorf("failed to create uploads directory: %w", err)
+	}
+
+	// 顺带清理过期的分片上传
+	s.cleanupStaleUploads()
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate upload id: %w", err)
+	}
+	id := hex.EncodeToString(buf)
+
+	path, _ := s.uploadPath(id)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload file: %w", err)
+	}
+	f.Close()
+
+	return id, nil
+}
+
+// UploadOffset 返回分片上传已接收的字节数，客户端据此断点续传
+func (s *HelmService) UploadOffset(id string) (int64, error) {
+	path, err := s.uploadPath(id)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, fmt.Errorf("%w: %s", ErrUploadNotFound, id)
+		}
+		return 0, fmt.Errorf("failed to stat upload: %w", err)
+	}
+
+	return info.Size(), nil
+}
+
+// WriteUploadChunk 在指定偏移处写入一个分片，返回写入后的总字节数。
+// maxSize 大于 0 时，写入后的文件不得超过该大小；同一上传的分片写入相互串行
+func (s *HelmService) WriteUploadChunk(id string, offset int64, chunk io.Reader, maxSize int64) (int64, error) {
+	if _, err := s.uploadPath(id); err != nil {
+		return 0, err
+	}
+	mu, _ := s.uploadLocks.LoadOrStore(id, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	defer mu.(*sync.Mutex).Unlock()
+
+	size, err := s.UploadOffset(id)
+	if err != nil {
+		return 0, err
+	}
+
+	// 允许重传已接收的部分，但不允许跳过中间的数据
+	if offset > size {
+		return size, fmt.Errorf("%w: expected offset <= %d, got %d", ErrUploadOffsetMismatch, size, offset)
+	}
+	if maxSize > 0 && offset >= maxSize {
+		return size, fmt.Errorf("%w: limit is %d bytes", ErrUploadTooLarge, maxSize)
+	}
+
+	path, _ := s.uploadPath(id)
+	f, err := os.OpenFile(path, os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open upload file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to seek upload file: %w", err)
+	}
+
+	// 多读一个字节用于判断分片是否超出剩余的额度
+	if maxSize > 0 {
+		chunk = io.LimitReader(chunk, maxSize-offset+1)
+	}
+	written, err := io.Copy(f, chunk)
+	if maxSize > 0 && offset+written > maxSize {
+		// 丢弃超出的部分，保留此前已接收的数据以便客户端处理
+		if err := f.Truncate(size); err != nil {
+			log.Printf("failed to truncate upload %s: %v", path, err)
+		}
+		return size, fmt.Errorf("%w: limit is %d bytes", ErrUploadTooLarge, maxSize)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write upload chunk: %w", err)
+	}
+
+	if end := offset + written; end > size {
+		size = end
+	}
+	return size, nil
+}
+
+// CompleteUpload 校验已组装的 Chart 包并将其保存到 charts 目录，返回保存的文件名
+func (s *HelmService) CompleteUpload(id string) (string, error) {
+	if _, err := s.UploadOffset(id); err != nil {
+		return "", err
+	}
+	path, _ := s.uploadPath(id)
+	defer os.Remove(path)
+	defer s.uploadLocks.Delete(id)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open upload file: %w", err)
+	}
+	defer f.Close()
+
+	if err := CheckArchiveEntries(f); err != nil {
+		return "", err
+	}
+	chart, err := loader.Load(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidChart, err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to read upload file: %w", err)
+	}
+
+	filename := fmt.Sprintf("%s-%s.tgz", chart.Metadata.Name, chart.Metadata.Version)
+	if err := s.UploadChart(f, filename); err != nil {
+		return "", err
+	}
+
+	return filename, nil
+}
+
+// UploadChartArchive 校验内存中的 Chart 包并按 <name>-<version>.tgz 保存，返回 Chart 的名称与版本
+func (s *HelmService) UploadChartArchive(data []byte) (string, string, error) {
+	if err := CheckArchiveEntries(bytes.NewReader(data)); err != nil {
+		return "", "", err
+	}
+	chart, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrInvalidChart, err)
+	}
+
+	filename := fmt.Sprintf("%s-%s.tgz", chart.Metadata.Name, chart.Metadata.Version)
+	if err := s.UploadChart(bytes.NewReader(data), filename); err != nil {
+		return "", "", err
+	}
+
+	return chart.Metadata.Name, chart.Metadata.Version, nil
+}
+
+// cleanupStaleUploads 删除超过保留时长仍未完成的分片上传
+func (s *HelmService) cleanupStaleUploads() {
+	entries, err := os.ReadDir(s.uploadsDir())
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < staleUploadAge {
+			continue
+		}
+
+		path := filepath.Join(s.uploadsDir(), entry.Name())
+		if err := os.Remove(path); err != nil {
+			log.Printf("failed to remove stale upload %s: %v", path, err)
+			continue
+		}
+		log.Printf("removed stale upload %s", path)
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriteUploadChunkLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		offset   int64
+		chunk    string
+		wantSize int64
+		wantErr  error
+	}{
+		{"within limit", 4, "5678", 8, nil},
+		{"exactly at limit", 4, "567890", 10, nil},
+		{"rewrite received data", 0, "ab", 4, nil},
+		{"chunk exceeds limit", 4, "5678901", 4, ErrUploadTooLarge},
+		{"offset at limit", 10, "", 4, ErrUploadOffsetMismatch},
+		{"offset beyond received", 6, "78", 4, ErrUploadOffsetMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &HelmService{tempDir: t.TempDir()}
+			id, err := s.InitUpload()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.WriteUploadChunk(id, 0, strings.NewReader("1234"), 10); err != nil {
+				t.Fatal(err)
+			}
+
+			size, err := s.WriteUploadChunk(id, tt.offset, strings.NewReader(tt.chunk), 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WriteUploadChunk() error = %v, want %v", err, tt.wantErr)
+			}
+			if size != tt.wantSize {
+				t.Errorf("WriteUploadChunk() size = %d, want %d", size, tt.wantSize)
+			}
+			// 被拒绝的分片不应留在磁盘上
+			if got, _ := s.UploadOffset(id); got != tt.wantSize {
+				t.Errorf("UploadOffset() = %d, want %d", got, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestWriteUploadChunkConcurrent(t *testing.T) {
+	s := &HelmService{tempDir: t.TempDir()}
+	id, err := s.InitUpload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 并发地在同一偏移写入，串行化后文件大小仍不超过上限
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.WriteUploadChunk(id, 0, strings.NewReader("0123456789"), 10)
+		}()
+	}
+	wg.Wait()
+
+	if got, _ := s.UploadOffset(id); got != 10 {
+		t.Errorf("UploadOffset() = %d, want 10", got)
+	}
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/strvals"
+)
+
+// MergeValues 深度合并两组 values，override 中的值优先
+func MergeValues(base, override map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		result[k] = v
+	}
+
+	for k, v := range override {
+		if overrideMap, ok := v.(map[string]interface{}); ok {
+			if baseMap, ok := result[k].(map[string]interface{}); ok {
+				result[k] = MergeValues(baseMap, overrideMap)
+				continue
+			}
+		}
+		result[k] = v
+	}
+
+	return result
+}
+
+const (
+	// maxSetValues 限制单个请求中 --set 风格覆盖的数量
+	maxSetValues = 50
+	// maxSetValueLength 限制单个 --set 风格覆盖的长度
+	maxSetValueLength = 1024
+)
+
+// ParseSetValues 按 helm --set 的语法解析 key=value 形式的覆盖，后面的优先；
+// 数量或长度超过限制、语法错误时返回 ErrInvalidValues
+func ParseSetValues(sets []string) (map[string]interface{}, error) {
+	if len(sets) > maxSetValues {
+		return nil, fmt.Errorf("%w: at most %d set overrides are allowed", ErrInvalidValues, maxSetValues)
+	}
+
+	values := map[string]interface{}{}
+	for _, set := range sets {
+		if len(set) > maxSetValueLength {
+			return nil, fmt.Errorf("%w: set override exceeds %d characters", ErrInvalidValues, maxSetValueLength)
+		}
+		if err := strvals.ParseInto(set, values); err != nil {
+			return nil, fmt.Errorf("%w: set %q: %v", ErrInvalidValues, set, err)
+		}
+	}
+	return values, nil
+}
+
+// InheritedValues 返回以基础 Chart 的默认 values 为底、目标 Chart 默认 values 覆盖其上的合并结果，
+// 作为渲染目标 Chart 时最底层的 values；目标 Chart 中的同名值优先
+func (s *HelmService) InheritedValues(baseName, baseVersion, name, version string) (map[string]interface{}, error) {
+	base, err := s.loadChart(baseName, baseVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load base chart: %w", err)
+	}
+	target, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+	return MergeValues(base.Values, target.Values), nil
+}
+
+// ChartValuesFiles 按顺序加载并合并 Chart 包内的 values 文件（如 values-prod.yaml），
+// 不存在或无法解析的文件会一并列在返回的 ErrInvalidValues 中
+func (s *HelmService) ChartValuesFiles(name, version string, files []string) (map[string]interface{}, error) {
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(map[string][]byte, len(chart.Raw))
+	for _, f := range chart.Raw {
+		raw[f.Name] = f.Data
+	}
+
+	values := map[string]interface{}{}
+	var problems []string
+	for _, file := range files {
+		data, ok := raw[file]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: not found in chart", file))
+			continue
+		}
+		fileValues, err := parseValues(data, file)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		values = MergeValues(values, fileValues)
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidValues, strings.Join(problems, "; "))
+	}
+	return values, nil
+}
+
+// 支持的数组合并策略
+const (
+	// ArrayMergeReplace 用新数组整体替换旧数组，与 helm 默认行为一致
+	ArrayMergeReplace = "replace"
+	// ArrayMergeAppend 将新数组的元素追加到旧数组之后
+	ArrayMergeAppend = "append"
+	// ArrayMergeByKey 按指定字段匹配元素，匹配到的元素深度合并，其余元素追加
+	ArrayMergeByKey = "merge-by-key"
+)
+
+// ArrayMergeOptions 定义合并 values 时数组的处理方式
+type ArrayMergeOptions struct {
+	// Strategy 为 replace（默认）、append 或 merge-by-key
+	Strategy string
+	// Keys 为 merge-by-key 策略下各数组路径（如 env、sidecars.containers）用于匹配元素的字段，
+	// 未配置字段的数组按 replace 处理
+	Keys map[string]string
+}
+
+// ValidArrayMergeStrategy 判断数组合并策略是否受支持，空字符串视为 replace
+func ValidArrayMergeStrategy(strategy string) bool {
+	switch strategy {
+	case "", ArrayMergeReplace, ArrayMergeAppend, ArrayMergeByKey:
+		return true
+	}
+	return false
+}
+
+// MergeValuesWithStrategy 深度合并两组 values，override 中的值优先，数组按 opts 指定的策略合并
+func MergeValuesWithStrategy(base, override map[string]interface{}, opts ArrayMergeOptions) map[string]interface{} {
+	return mergeValuesAt(base, override, "", opts)
+}
+
+// mergeValuesAt 在 path 处合并两组 values
+func mergeValuesAt(base, override map[string]interface{}, path string, opts ArrayMergeOptions) map[string]interface{} {
+	result := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		result[k] = v
+	}
+
+	for k, v := range override {
+		childPath := k
+		if path != "" {
+			childPath = path + "." + k
+		}
+
+		switch overrideValue := v.(type) {
+		case map[string]interface{}:
+			if baseMap, ok := result[k].(map[string]interface{}); ok {
+				result[k] = mergeValuesAt(baseMap, overrideValue, childPath, opts)
+				continue
+			}
+		case []interface{}:
+			if baseList, ok := result[k].([]interface{}); ok {
+				result[k] = mergeArrays(baseList, overrideValue, childPath, opts)
+				continue
+			}
+		}
+		result[k] = v
+	}
+
+	return result
+}
+
+// mergeArrays 按策略合并 path 处的两个数组
+func mergeArrays(base, override []interface{}, path string, opts ArrayMergeOptions) []interface{} {
+	switch opts.Strategy {
+	case ArrayMergeAppend:
+		result := make([]interface{}, 0, len(base)+len(override))
+		result = append(result, base...)
+		return append(result, override...)
+	case ArrayMergeByKey:
+		key, ok := opts.Keys[path]
+		if !ok || key == "" {
+			return override
+		}
+		return mergeArraysByKey(base, override, key, path, opts)
+	default:
+		return override
+	}
+}
+
+// mergeArraysByKey 按 key 字段匹配数组元素，匹配到的元素深度合并并保持原位置，未匹配的元素追加到末尾
+func mergeArraysByKey(base, override []interface{}, key, path string, opts ArrayMergeOptions) []interface{} {
+	result := make([]interface{}, len(base))
+	copy(result, base)
+
+	index := map[interface{}]int{}
+	for i, item := range result {
+		if m, ok := item.(map[string]interface{}); ok {
+			if id, ok := m[key]; ok && isComparable(id) {
+				index[id] = i
+			}
+		}
+	}
+
+	for _, item := range override {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			result = append(result, item)
+			continue
+		}
+		id, ok := m[key]
+		if !ok || !isComparable(id) {
+			result = append(result, item)
+			continue
+		}
+		if i, found := index[id]; found {
+			result[i] = mergeValuesAt(result[i].(map[string]interface{}), m, path, opts)
+			continue
+		}
+		index[id] = len(result)
+		result = append(result, item)
+	}
+
+	return result
+}
+
+// isComparable 判断 values 中的标量能否作为匹配字段
+func isComparable(v interface{}) bool {
+	switch v.(type) {
+	case string, bool, int, int64, float64:
+		return true
+	}
+	return false
+}
+
+// CoalescedValues 返回 helm 渲染时实际使用的 values：在整个依赖树上合并父 Chart、子 Chart 默认值、
+// global 以及 overrides，被禁用的子 Chart 不会出现在结果中
+func (s *HelmService) CoalescedValues(name, version string, overrides map[string]interface{}) (map[string]interface{}, error) {
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if overrides == nil {
+		overrides = map[string]interface{}{}
+	}
+
+	if err := chartutil.ProcessDependenciesWithMerge(chart, overrides); err != nil {
+		return nil, fmt.Errorf("failed to process dependencies: %w", err)
+	}
+
+	values, err := chartutil.CoalesceValues(chart, overrides)
+	if err != nil {
+		return nil, fmt.Errorf("failed to coalesce values: %w", err)
+	}
+
+	return values.AsMap(), nil
+}
+
+// FullDefaultValues 返回整个依赖树合并后的默认 values：子 Chart 的默认值位于其别名（未设置别名时为名称）下，
+// 父 Chart 中的同名值优先。与 CoalescedValues 不同，被 condition 或 tags 禁用的子 Chart 也会包含在内，
+// 以便展示全部可编辑的 values
+func (s *HelmService) FullDefaultValues(name, version string) (map[string]interface{}, error) {
+	c, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := chartutil.CoalesceValues(aliasedChart(c), map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to coalesce values: %w", err)
+	}
+	return values.AsMap(), nil
+}
+
+// aliasedChart 返回 Chart 的副本，其依赖按 Chart.yaml 中声明的别名重命名（同一 Chart 可对应多个别名），
+// 未声明的已打包子 Chart 保留原名，声明但未打包的依赖被忽略。原 Chart 不会被修改
+func aliasedChart(c *chart.Chart) *chart.Chart {
+	out := *c
+	var deps []*chart.Chart
+	declared := map[string]bool{}
+	for _, dep := range c.Metadata.Dependencies {
+		declared[dep.Name] = true
+		for _, sub := range c.Dependencies() {
+			if sub.Metadata.Name != dep.Name {
+				continue
+			}
+			aliased := aliasedChart(sub)
+			if dep.Alias != "" {
+				md := *aliased.Metadata
+				md.Name = dep.Alias
+				aliased.Metadata = &md
+			}
+			deps = append(deps, aliased)
+			break
+		}
+	}
+	for _, sub := range c.Dependencies() {
+		if !declared[sub.Metadata.Name] {
+			deps = append(deps, aliasedChart(sub))
+		}
+	}
+	out.SetDependencies(deps...)
+	return &out
+}
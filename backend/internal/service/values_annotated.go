@@ -0,0 +1,111 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GetAnnotatedValues 返回 Chart 默认的 values.yaml，并将 values.schema.json 中对应路径的描述和类型
+// 以注释形式写在各 key 上方；schema 与 values 结构不一致的部分保持原样
+func (s *HelmService) GetAnnotatedValues(name, version string) ([]byte, error) {
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	for _, f := range chart.Raw {
+		if f.Name == "values.yaml" {
+			data = f.Data
+			break
+		}
+	}
+	if len(chart.Schema) == 0 || len(bytes.TrimSpace(data)) == 0 {
+		return data, nil
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(chart.Schema, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse values.schema.json: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse values.yaml: %w", err)
+	}
+	for _, doc := range root.Content {
+		annotateValuesNode(doc, schema)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return nil, fmt.Errorf("failed to encode values: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode values: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// annotateValuesNode 同时遍历 values 节点与对应的 schema，为匹配的 key 添加注释
+func annotateValuesNode(node *yaml.Node, schema map[string]interface{}) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			child, ok := properties[key.Value].(map[string]interface{})
+			if !ok {
+				child = additional
+			}
+			if child == nil {
+				continue
+			}
+			if comment := schemaComment(child); comment != "" {
+				key.HeadComment = strings.TrimSpace(key.HeadComment + "\n" + comment)
+			}
+			annotateValuesNode(value, child)
+		}
+	case yaml.SequenceNode:
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, item := range node.Content {
+			annotateValuesNode(item, items)
+		}
+	}
+}
+
+// schemaComment 根据 schema 的 description 与 type 生成注释
+func schemaComment(schema map[string]interface{}) string {
+	var lines []string
+	if description, ok := schema["description"].(string); ok && description != "" {
+		for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+			lines = append(lines, "# "+strings.TrimSpace(line))
+		}
+	}
+
+	switch t := schema["type"].(type) {
+	case string:
+		lines = append(lines, "# type: "+t)
+	case []interface{}:
+		var types []string
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		if len(types) > 0 {
+			lines = append(lines, "# type: "+strings.Join(types, " | "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
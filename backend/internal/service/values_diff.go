@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+// DiffValuesRender 分别使用 base 以及合并了 override 的 values 并发渲染 Chart，
+// 返回两次渲染结果的统一 diff，输出相同时返回空字符串
+func (s *HelmService) DiffValuesRender(ctx context.Context, name, version string, base, override map[string]interface{}, releaseName, namespace string) (string, error) {
+	var (
+		wg      sync.WaitGroup
+		results [2]*RenderResult
+		errs    [2]error
+	)
+	for i, values := range []map[string]interface{}{base, MergeValues(base, override)} {
+		wg.Add(1)
+		go func(i int, values map[string]interface{}) {
+			defer wg.Done()
+			results[i], errs[i] = s.RenderChartContext(ctx, name, version, values, releaseName, namespace, RenderOptions{})
+		}(i, values)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return unifiedDiff("base", "override", results[0].Manifest, results[1].Manifest)
+}
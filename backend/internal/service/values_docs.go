@@ -0,0 +1,98 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GetValuesDocs 解析 values.yaml 中 helm-docs 风格的 "# --" 注释，返回 value 路径到描述的映射
+func (s *HelmService) GetValuesDocs(name, version string) (map[string]string, error) {
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := map[string]string{}
+	for _, f := range chart.Raw {
+		if f.Name != "values.yaml" {
+			continue
+		}
+
+		var root yaml.Node
+		if err := yaml.Unmarshal(f.Data, &root); err != nil {
+			return nil, fmt.Errorf("failed to parse values.yaml: %w", err)
+		}
+		collectValuesDocs(&root, "", docs)
+	}
+
+	return docs, nil
+}
+
+// collectValuesDocs 递归遍历 YAML 节点，收集每个 key 上方的文档注释
+func collectValuesDocs(node *yaml.Node, prefix string, docs map[string]string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			collectValuesDocs(child, prefix, docs)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			path := key.Value
+			if prefix != "" {
+				path = prefix + "." + key.Value
+			}
+
+			// 映射中第一个 key 的注释可能被挂在父节点上
+			comment := key.HeadComment
+			if i == 0 && comment == "" {
+				comment = node.HeadComment
+			}
+			if doc := parseValueDoc(comment); doc != "" {
+				docs[path] = doc
+			}
+
+			collectValuesDocs(value, path, docs)
+		}
+	}
+}
+
+// parseValueDoc 从注释块中提取最后一个 "# --" 描述，支持多行续写和 @default 注解
+func parseValueDoc(comment string) string {
+	var (
+		lines      []string
+		defaultVal string
+		inDoc      bool
+	)
+
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "#") {
+			inDoc = false
+			continue
+		}
+		text := strings.TrimSpace(strings.TrimPrefix(line, "#"))
+
+		switch {
+		case strings.HasPrefix(text, "-- ") || text == "--":
+			// 新的描述块覆盖之前的内容
+			lines = []string{strings.TrimSpace(strings.TrimPrefix(text, "--"))}
+			defaultVal = ""
+			inDoc = true
+		case strings.HasPrefix(text, "@default"):
+			if inDoc {
+				defaultVal = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(text, "@default"), " --"))
+			}
+		case inDoc && !strings.HasPrefix(text, "@"):
+			lines = append(lines, text)
+		}
+	}
+
+	doc := strings.TrimSpace(strings.Join(lines, " "))
+	if defaultVal != "" {
+		doc = strings.TrimSpace(fmt.Sprintf("%s (default: %s)", doc, defaultVal))
+	}
+	return doc
+}
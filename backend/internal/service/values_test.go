@@ -0,0 +1,339 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestMergeValuesWithStrategy(t *testing.T) {
+	env := func(name, value string) map[string]interface{} {
+		return map[string]interface{}{"name": name, "value": value}
+	}
+	base := map[string]interface{}{
+		"env":   []interface{}{env("A", "1"), env("B", "2")},
+		"ports": []interface{}{80},
+		"nested": map[string]interface{}{
+			"list": []interface{}{"x"},
+		},
+	}
+	override := map[string]interface{}{
+		"env":   []interface{}{env("B", "20"), env("C", "3")},
+		"ports": []interface{}{443},
+		"nested": map[string]interface{}{
+			"list": []interface{}{"y"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts ArrayMergeOptions
+		want map[string]interface{}
+	}{
+		{
+			name: "replace",
+			opts: ArrayMergeOptions{},
+			want: map[string]interface{}{
+				"env":    []interface{}{env("B", "20"), env("C", "3")},
+				"ports":  []interface{}{443},
+				"nested": map[string]interface{}{"list": []interface{}{"y"}},
+			},
+		},
+		{
+			name: "append",
+			opts: ArrayMergeOptions{Strategy: ArrayMergeAppend},
+			want: map[string]interface{}{
+				"env":    []interface{}{env("A", "1"), env("B", "2"), env("B", "20"), env("C", "3")},
+				"ports":  []interface{}{80, 443},
+				"nested": map[string]interface{}{"list": []interface{}{"x", "y"}},
+			},
+		},
+		{
+			name: "merge by key, other arrays replaced",
+			opts: ArrayMergeOptions{Strategy: ArrayMergeByKey, Keys: map[string]string{"env": "name"}},
+			want: map[string]interface{}{
+				"env":    []interface{}{env("A", "1"), env("B", "20"), env("C", "3")},
+				"ports":  []interface{}{443},
+				"nested": map[string]interface{}{"list": []interface{}{"y"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeValuesWithStrategy(base, override, tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeValuesWithStrategy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 合并不应修改输入
+	if got := base["env"].([]interface{})[1]; !reflect.DeepEqual(got, env("B", "2")) {
+		t.Errorf("base was modified: %v", got)
+	}
+}
+
+func TestMergeArraysByKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     []interface{}
+		override []interface{}
+		want     []interface{}
+	}{
+		{
+			name:     "matched items merged in place",
+			base:     []interface{}{map[string]interface{}{"name": "a", "x": 1, "y": 1}},
+			override: []interface{}{map[string]interface{}{"name": "a", "y": 2}},
+			want:     []interface{}{map[string]interface{}{"name": "a", "x": 1, "y": 2}},
+		},
+		{
+			name:     "items without key appended",
+			base:     []interface{}{map[string]interface{}{"name": "a"}},
+			override: []interface{}{map[string]interface{}{"other": "b"}, "scalar"},
+			want:     []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"other": "b"}, "scalar"},
+		},
+		{
+			name:     "non-comparable key appended",
+			base:     []interface{}{map[string]interface{}{"name": []interface{}{"a"}}},
+			override: []interface{}{map[string]interface{}{"name": []interface{}{"a"}}},
+			want:     []interface{}{map[string]interface{}{"name": []interface{}{"a"}}, map[string]interface{}{"name": []interface{}{"a"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeArraysByKey(tt.base, tt.override, "name", "list", ArrayMergeOptions{Strategy: ArrayMergeByKey})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeArraysByKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidArrayMergeStrategy(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     bool
+	}{
+		{"", true},
+		{ArrayMergeReplace, true},
+		{ArrayMergeAppend, true},
+		{ArrayMergeByKey, true},
+		{"prepend", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidArrayMergeStrategy(tt.strategy); got != tt.want {
+			t.Errorf("ValidArrayMergeStrategy(%q) = %v, want %v", tt.strategy, got, tt.want)
+		}
+	}
+}
+
+func TestRenderChartArrayMerge(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "ports", Version: "0.1.0"},
+		Values:   map[string]interface{}{"ports": []interface{}{80}},
+		// chartutil.Save 从 Raw 写出 values.yaml
+		Raw:       []*chart.File{{Name: "values.yaml", Data: []byte("ports: [80]\n")}},
+		Templates: []*chart.File{{Name: "templates/cm.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: ports\ndata:\n  ports: {{ .Values.ports | join \",\" | quote }}\n")}},
+	}
+	s := newRenderTestService(t, c)
+
+	tests := []struct {
+		strategy string
+		want     string
+	}{
+		{ArrayMergeReplace, `ports: "443"`},
+		{ArrayMergeAppend, `ports: "80,443"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			values := map[string]interface{}{"ports": []interface{}{443}}
+			result, err := s.RenderChart("ports", "0.1.0", values, "r", "default", RenderOptions{ArrayMerge: ArrayMergeOptions{Strategy: tt.strategy}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(result.Manifest, tt.want) {
+				t.Errorf("manifest does not contain %s:\n%s", tt.want, result.Manifest)
+			}
+		})
+	}
+}
+
+func TestParseSetValues(t *testing.T) {
+	tooMany := make([]string, maxSetValues+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("k%d=v", i)
+	}
+
+	tests := []struct {
+		name    string
+		sets    []string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{"none", nil, map[string]interface{}{}, false},
+		{"nested and typed", []string{"image.tag=1.2.3", "replicas=3", "debug=true"}, map[string]interface{}{
+			"image": map[string]interface{}{"tag": "1.2.3"}, "replicas": int64(3), "debug": true,
+		}, false},
+		{"later set wins", []string{"replicas=1", "replicas=2"}, map[string]interface{}{"replicas": int64(2)}, false},
+		{"multiple keys in one set", []string{"a=1,b=2"}, map[string]interface{}{"a": int64(1), "b": int64(2)}, false},
+		{"list index", []string{"hosts[0]=a.example.com"}, map[string]interface{}{"hosts": []interface{}{"a.example.com"}}, false},
+		{"syntax error", []string{"a[=1"}, nil, true},
+		{"too long", []string{"a=" + strings.Repeat("x", maxSetValueLength)}, nil, true},
+		{"too many", tooMany, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSetValues(tt.sets)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidValues) {
+					t.Fatalf("ParseSetValues() error = %v, want %v", err, ErrInvalidValues)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSetValues() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFullDefaultValues(t *testing.T) {
+	// withValues 返回带有默认 values 的 Chart，chartutil.Save 从 Raw 写出 values.yaml
+	withValues := func(name, values string) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: name, Version: "1.0.0"},
+			Raw:      []*chart.File{{Name: "values.yaml", Data: []byte(values)}},
+		}
+	}
+	parent := withValues("parent", "replicas: 1\nredis:\n  enabled: false\ncache:\n  port: 7000\n")
+	parent.Metadata.Version = "0.1.0"
+	parent.Metadata.Dependencies = []*chart.Dependency{
+		{Name: "postgresql", Version: "1.0.0"},
+		{Name: "redis", Version: "1.0.0", Alias: "cache"},
+		{Name: "redis", Version: "1.0.0", Condition: "redis.enabled"},
+	}
+	parent.SetDependencies(
+		withValues("postgresql", "port: 5432\nauth:\n  username: app\n"),
+		withValues("redis", "port: 6379\n"),
+	)
+	s := newRenderTestService(t, parent)
+
+	got, err := s.FullDefaultValues("parent", "0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"replicas", float64(1)},
+		{"postgresql.port", float64(5432)},
+		{"postgresql.auth.username", "app"},
+		// 父 Chart 中的同名值优先
+		{"cache.port", float64(7000)},
+		// 被 condition 禁用的子 Chart 也包含其默认值
+		{"redis.port", float64(6379)},
+		{"redis.enabled", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			v, err := chartutil.Values(got).PathValue(tt.path)
+			if err != nil {
+				t.Fatalf("%s: %v in %v", tt.path, err, got)
+			}
+			if !reflect.DeepEqual(v, tt.want) {
+				t.Errorf("%s = %#v, want %#v", tt.path, v, tt.want)
+			}
+		})
+	}
+}
+
+func TestAliasedChartDoesNotModifyChart(t *testing.T) {
+	sub := &chart.Chart{Metadata: &chart.Metadata{Name: "redis", Version: "1.0.0"}}
+	parent := &chart.Chart{Metadata: &chart.Metadata{
+		Name: "parent", Version: "0.1.0",
+		Dependencies: []*chart.Dependency{{Name: "redis", Alias: "cache"}, {Name: "redis", Alias: "queue"}},
+	}}
+	parent.SetDependencies(sub)
+
+	aliased := aliasedChart(parent)
+	var names []string
+	for _, dep := range aliased.Dependencies() {
+		names = append(names, dep.Name())
+	}
+	if want := []string{"cache", "queue"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("aliased dependencies = %v, want %v", names, want)
+	}
+	if sub.Name() != "redis" || len(parent.Dependencies()) != 1 {
+		t.Errorf("original chart was modified: %s, %d dependencies", sub.Name(), len(parent.Dependencies()))
+	}
+}
+
+func TestInheritedValues(t *testing.T) {
+	base := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "base", Version: "1.0.0"},
+		Raw:      []*chart.File{{Name: "values.yaml", Data: []byte("team: platform\nreplicas: 5\nimage:\n  registry: registry.example.com\n  tag: base\n")}},
+	}
+	target := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "app", Version: "0.1.0"},
+		Raw:      []*chart.File{{Name: "values.yaml", Data: []byte("replicas: 2\nimage:\n  tag: app\n")}},
+		Templates: []*chart.File{{Name: "templates/cm.yaml", Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  team: {{ .Values.team }}
+  replicas: "{{ .Values.replicas }}"
+  image: {{ .Values.image.registry }}:{{ .Values.image.tag }}
+`)}},
+	}
+	s := newRenderTestService(t, base, target)
+
+	inherited, err := s.InheritedValues("base", "1.0.0", "app", "0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 优先级：基础 Chart 默认值 < 目标 Chart 默认值 < 用户 values
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		want   []string
+	}{
+		{"inherited defaults show through", nil, []string{"team: platform", `replicas: "2"`, "image: registry.example.com:app"}},
+		{"user values win", map[string]interface{}{"replicas": 3, "image": map[string]interface{}{"tag": "user"}}, []string{"team: platform", `replicas: "3"`, "image: registry.example.com:user"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.RenderChart("app", "0.1.0", MergeValues(inherited, tt.values), "r", "default", RenderOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(result.Manifest, want) {
+					t.Errorf("manifest does not contain %q:\n%s", want, result.Manifest)
+				}
+			}
+		})
+	}
+
+	if _, err := s.InheritedValues("missing", "1.0.0", "app", "0.1.0"); !errors.Is(err, ErrChartNotFound) {
+		t.Errorf("InheritedValues() with a missing base chart error = %v, want %v", err, ErrChartNotFound)
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import "sort"
+
+// TypeNode 描述 values 中一个 key 的推断类型，用于在没有 schema 时生成编辑表单
+type TypeNode struct {
+	Key string `json:"key"`
+	// Type 为 string/number/bool/object/array，值为 null 或数组元素类型不一致时为 unknown
+	Type    string      `json:"type"`
+	Default interface{} `json:"default,omitempty"`
+	// Children 为 object 的子 key，按名称排序
+	Children []TypeNode `json:"children,omitempty"`
+	// Items 描述 array 的元素类型，对象数组会合并所有元素的 key
+	Items *TypeNode `json:"items,omitempty"`
+}
+
+// ValuesTypeTree 根据 Chart 默认 values 推断每个 key 的类型，返回以空 key 为根的类型树
+func (s *HelmService) ValuesTypeTree(name, version string) (TypeNode, error) {
+	chart, err := s.loadChart(name, version)
+	if err != nil {
+		return TypeNode{}, err
+	}
+	return valuesTypeNode("", map[string]interface{}(chart.Values)), nil
+}
+
+// valuesTypeNode 推断单个值的类型节点
+func valuesTypeNode(key string, v interface{}) TypeNode {
+	node := TypeNode{Key: key, Type: typeTreeKind(v)}
+	switch val := v.(type) {
+	case map[string]interface{}:
+		node.Children = objectTypeChildren([]map[string]interface{}{val})
+	case []interface{}:
+		node.Default = val
+		node.Items = arrayItemType(val)
+	default:
+		node.Default = val
+	}
+	return node
+}
+
+// objectTypeChildren 合并多个对象的 key 并推断类型，同名 key 以第一个非 null 的值为准
+func objectTypeChildren(objects []map[string]interface{}) []TypeNode {
+	seen := map[string]bool{}
+	var keys []string
+	for _, obj := range objects {
+		for k := range obj {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	children := make([]TypeNode, 0, len(keys))
+	for _, k := range keys {
+		var values []interface{}
+		for _, obj := range objects {
+			if v, ok := obj[k]; ok {
+				values = append(values, v)
+			}
+		}
+		children = append(children, mergedTypeNode(k, values))
+	}
+	return children
+}
+
+// arrayItemType 推断数组元素的类型，空数组返回 nil
+func arrayItemType(items []interface{}) *TypeNode {
+	if len(items) == 0 {
+		return nil
+	}
+	node := mergedTypeNode("", items)
+	// 元素的默认值已包含在数组默认值中
+	node.Default = nil
+	return &node
+}
+
+// mergedTypeNode 将多个值合并为一个类型节点：null 不参与推断，类型不一致时为 unknown
+func mergedTypeNode(key string, values []interface{}) TypeNode {
+	var nonNull []interface{}
+	for _, v := range values {
+		if v != nil {
+			nonNull = append(nonNull, v)
+		}
+	}
+	if len(nonNull) == 0 {
+		return TypeNode{Key: key, Type: "unknown"}
+	}
+
+	kind := typeTreeKind(nonNull[0])
+	for _, v := range nonNull[1:] {
+		if typeTreeKind(v) != kind {
+			return TypeNode{Key: key, Type: "unknown"}
+		}
+	}
+
+	switch kind {
+	case "object":
+		objects := make([]map[string]interface{}, 0, len(nonNull))
+		for _, v := range nonNull {
+			objects = append(objects, v.(map[string]interface{}))
+		}
+		return TypeNode{Key: key, Type: kind, Children: objectTypeChildren(objects)}
+	case "array":
+		var items []interface{}
+		for _, v := range nonNull {
+			items = append(items, v.([]interface{})...)
+		}
+		return TypeNode{Key: key, Type: kind, Default: nonNull[0], Items: arrayItemType(items)}
+	default:
+		return TypeNode{Key: key, Type: kind, Default: nonNull[0]}
+	}
+}
+
+// typeTreeKind 返回类型树中使用的类型名称
+func typeTreeKind(v interface{}) string {
+	switch kind := valueKind(v); kind {
+	case "":
+		return "unknown"
+	case "boolean":
+		return "bool"
+	case "string", "number", "object", "array":
+		return kind
+	default:
+		return "unknown"
+	}
+}